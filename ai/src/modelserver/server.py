@@ -1,4 +1,5 @@
 import grpc
+import json
 from concurrent import futures
 import time
 
@@ -13,10 +14,14 @@ class AIServiceServicer(ai_pb2_grpc.AIServiceServicer):
     def AnalyzeCode(self, request, context):
         print(f"[AIService] Received AnalyzeCode for language={request.language}")
 
+        # Repository .unarya.yml, already validated by the orchestrator
+        repo_config = json.loads(request.repo_config) if request.repo_config else {}
+
         result = self.engine.infer({
             "operation": "AnalyzeCode",
             "language": request.language,
-            "code_structure": request.code_structure
+            "code_structure": request.code_structure,
+            "deploy_artifacts": repo_config.get("deploy", {}).get("artifacts", [])
         })
 
         # Check success first
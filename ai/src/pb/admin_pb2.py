@@ -0,0 +1,47 @@
+# -*- coding: utf-8 -*-
+# Generated by the protocol buffer compiler.  DO NOT EDIT!
+# NO CHECKED-IN PROTOBUF GENCODE
+# source: admin.proto
+# Protobuf Python Version: 6.31.1
+"""Generated protocol buffer code."""
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import descriptor_pool as _descriptor_pool
+from google.protobuf import runtime_version as _runtime_version
+from google.protobuf import symbol_database as _symbol_database
+from google.protobuf.internal import builder as _builder
+_runtime_version.ValidateProtobufRuntimeVersion(
+    _runtime_version.Domain.PUBLIC,
+    6,
+    31,
+    1,
+    '',
+    'admin.proto'
+)
+# @@protoc_insertion_point(imports)
+
+_sym_db = _symbol_database.Default()
+
+
+
+
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x61\x64min.proto\x12\x07\x61\x64minpb\"\x0f\n\rIntakeRequest\"C\n\x0cIntakeStatus\x12\x0e\n\x06paused\x18\x01 \x01(\x08\x12\x10\n\x08\x64raining\x18\x02 \x01(\x08\x12\x11\n\tin_flight\x18\x03 \x01(\x05\"T\n\nAuditQuery\x12\x0f\n\x07subject\x18\x01 \x01(\t\x12\x12\n\nsince_unix\x18\x02 \x01(\x03\x12\x12\n\nuntil_unix\x18\x03 \x01(\x03\x12\r\n\x05limit\x18\x04 \x01(\x05\"\x9e\x01\n\x0b\x41uditRecord\x12\x16\n\x0etime_unix_nano\x18\x01 \x01(\x03\x12\x0f\n\x07subject\x18\x02 \x01(\t\x12\x12\n\ncredential\x18\x03 \x01(\t\x12\x0e\n\x06method\x18\x04 \x01(\t\x12\x12\n\nrepository\x18\x05 \x01(\t\x12\x0e\n\x06job_id\x18\x06 \x01(\t\x12\x0f\n\x07outcome\x18\x07 \x01(\t\x12\r\n\x05\x65rror\x18\x08 \x01(\t\"5\n\x0c\x41uditRecords\x12%\n\x07records\x18\x01 \x03(\x0b\x32\x14.adminpb.AuditRecord2\x87\x02\n\x0c\x41\x64minService\x12<\n\x0bPauseIntake\x12\x16.adminpb.IntakeRequest\x1a\x15.adminpb.IntakeStatus\x12=\n\x0cResumeIntake\x12\x16.adminpb.IntakeRequest\x1a\x15.adminpb.IntakeStatus\x12@\n\x0fGetIntakeStatus\x12\x16.adminpb.IntakeRequest\x1a\x15.adminpb.IntakeStatus\x12\x38\n\nQueryAudit\x12\x13.adminpb.AuditQuery\x1a\x15.adminpb.AuditRecordsB/Z-github.com/unarya/unarya/lib/proto/pb/adminpbb\x06proto3')
+
+_globals = globals()
+_builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
+_builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'admin_pb2', _globals)
+if not _descriptor._USE_C_DESCRIPTORS:
+  _globals['DESCRIPTOR']._loaded_options = None
+  _globals['DESCRIPTOR']._serialized_options = b'Z-github.com/unarya/unarya/lib/proto/pb/adminpb'
+  _globals['_INTAKEREQUEST']._serialized_start=24
+  _globals['_INTAKEREQUEST']._serialized_end=39
+  _globals['_INTAKESTATUS']._serialized_start=41
+  _globals['_INTAKESTATUS']._serialized_end=108
+  _globals['_AUDITQUERY']._serialized_start=110
+  _globals['_AUDITQUERY']._serialized_end=194
+  _globals['_AUDITRECORD']._serialized_start=197
+  _globals['_AUDITRECORD']._serialized_end=355
+  _globals['_AUDITRECORDS']._serialized_start=357
+  _globals['_AUDITRECORDS']._serialized_end=410
+  _globals['_ADMINSERVICE']._serialized_start=413
+  _globals['_ADMINSERVICE']._serialized_end=676
+# @@protoc_insertion_point(module_scope)
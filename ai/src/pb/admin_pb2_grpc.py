@@ -0,0 +1,236 @@
+# Generated by the gRPC Python protocol compiler plugin. DO NOT EDIT!
+"""Client and server classes corresponding to protobuf-defined services."""
+import grpc
+import warnings
+
+from . import admin_pb2 as admin__pb2
+
+GRPC_GENERATED_VERSION = '1.75.1'
+GRPC_VERSION = grpc.__version__
+_version_not_supported = False
+
+try:
+    from grpc._utilities import first_version_is_lower
+    _version_not_supported = first_version_is_lower(GRPC_VERSION, GRPC_GENERATED_VERSION)
+except ImportError:
+    _version_not_supported = True
+
+if _version_not_supported:
+    raise RuntimeError(
+        f'The grpc package installed is at version {GRPC_VERSION},'
+        + f' but the generated code in admin_pb2_grpc.py depends on'
+        + f' grpcio>={GRPC_GENERATED_VERSION}.'
+        + f' Please upgrade your grpc module to grpcio>={GRPC_GENERATED_VERSION}'
+        + f' or downgrade your generated code using grpcio-tools<={GRPC_VERSION}.'
+    )
+
+
+class AdminServiceStub(object):
+    """--- Admin Service ---
+    Operational controls exposed by every Unarya service
+    """
+
+    def __init__(self, channel):
+        """Constructor.
+
+        Args:
+            channel: A grpc.Channel.
+        """
+        self.PauseIntake = channel.unary_unary(
+                '/adminpb.AdminService/PauseIntake',
+                request_serializer=admin__pb2.IntakeRequest.SerializeToString,
+                response_deserializer=admin__pb2.IntakeStatus.FromString,
+                _registered_method=True)
+        self.ResumeIntake = channel.unary_unary(
+                '/adminpb.AdminService/ResumeIntake',
+                request_serializer=admin__pb2.IntakeRequest.SerializeToString,
+                response_deserializer=admin__pb2.IntakeStatus.FromString,
+                _registered_method=True)
+        self.GetIntakeStatus = channel.unary_unary(
+                '/adminpb.AdminService/GetIntakeStatus',
+                request_serializer=admin__pb2.IntakeRequest.SerializeToString,
+                response_deserializer=admin__pb2.IntakeStatus.FromString,
+                _registered_method=True)
+        self.QueryAudit = channel.unary_unary(
+                '/adminpb.AdminService/QueryAudit',
+                request_serializer=admin__pb2.AuditQuery.SerializeToString,
+                response_deserializer=admin__pb2.AuditRecords.FromString,
+                _registered_method=True)
+
+
+class AdminServiceServicer(object):
+    """--- Admin Service ---
+    Operational controls exposed by every Unarya service
+    """
+
+    def PauseIntake(self, request, context):
+        """Stop accepting new jobs; jobs already running keep going
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ResumeIntake(self, request, context):
+        """Start accepting new jobs again
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetIntakeStatus(self, request, context):
+        """Report whether intake is open and how many jobs are in flight
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def QueryAudit(self, request, context):
+        """Read the service's audit log, filtered by subject and time range
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+
+def add_AdminServiceServicer_to_server(servicer, server):
+    rpc_method_handlers = {
+            'PauseIntake': grpc.unary_unary_rpc_method_handler(
+                    servicer.PauseIntake,
+                    request_deserializer=admin__pb2.IntakeRequest.FromString,
+                    response_serializer=admin__pb2.IntakeStatus.SerializeToString,
+            ),
+            'ResumeIntake': grpc.unary_unary_rpc_method_handler(
+                    servicer.ResumeIntake,
+                    request_deserializer=admin__pb2.IntakeRequest.FromString,
+                    response_serializer=admin__pb2.IntakeStatus.SerializeToString,
+            ),
+            'GetIntakeStatus': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetIntakeStatus,
+                    request_deserializer=admin__pb2.IntakeRequest.FromString,
+                    response_serializer=admin__pb2.IntakeStatus.SerializeToString,
+            ),
+            'QueryAudit': grpc.unary_unary_rpc_method_handler(
+                    servicer.QueryAudit,
+                    request_deserializer=admin__pb2.AuditQuery.FromString,
+                    response_serializer=admin__pb2.AuditRecords.SerializeToString,
+            ),
+    }
+    generic_handler = grpc.method_handlers_generic_handler(
+            'adminpb.AdminService', rpc_method_handlers)
+    server.add_generic_rpc_handlers((generic_handler,))
+    server.add_registered_method_handlers('adminpb.AdminService', rpc_method_handlers)
+
+
+ # This class is part of an EXPERIMENTAL API.
+class AdminService(object):
+    """--- Admin Service ---
+    Operational controls exposed by every Unarya service
+    """
+
+    @staticmethod
+    def PauseIntake(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/adminpb.AdminService/PauseIntake',
+            admin__pb2.IntakeRequest.SerializeToString,
+            admin__pb2.IntakeStatus.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ResumeIntake(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/adminpb.AdminService/ResumeIntake',
+            admin__pb2.IntakeRequest.SerializeToString,
+            admin__pb2.IntakeStatus.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def GetIntakeStatus(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/adminpb.AdminService/GetIntakeStatus',
+            admin__pb2.IntakeRequest.SerializeToString,
+            admin__pb2.IntakeStatus.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def QueryAudit(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/adminpb.AdminService/QueryAudit',
+            admin__pb2.AuditQuery.SerializeToString,
+            admin__pb2.AuditRecords.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
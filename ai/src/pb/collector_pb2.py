@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0f\x63ollector.proto\x12\x0b\x63ollectorpb\"8\n\nGitRequest\x12\x0b\n\x03url\x18\x01 \x01(\t\x12\x0e\n\x06\x62ranch\x18\x02 \x01(\t\x12\r\n\x05token\x18\x03 \x01(\t\"\x1d\n\x0e\x41rchiveRequest\x12\x0b\n\x03url\x18\x01 \x01(\t\"\x19\n\nURLRequest\x12\x0b\n\x03url\x18\x01 \x01(\t\"\x1e\n\x0fValidateRequest\x12\x0b\n\x03url\x18\x01 \x01(\t\"2\n\x10ValidateResponse\x12\r\n\x05valid\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\"2\n\x11\x43ollectorResponse\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x0c\n\x04path\x18\x02 \x01(\t2\xca\x02\n\x10\x43ollectorService\x12I\n\x0e\x43ollectFromGit\x12\x17.collectorpb.GitRequest\x1a\x1e.collectorpb.CollectorResponse\x12Q\n\x12\x43ollectFromArchive\x12\x1b.collectorpb.ArchiveRequest\x1a\x1e.collectorpb.CollectorResponse\x12I\n\x0e\x43ollectFromURL\x12\x17.collectorpb.URLRequest\x1a\x1e.collectorpb.CollectorResponse\x12M\n\x0eValidateSource\x12\x1c.collectorpb.ValidateRequest\x1a\x1d.collectorpb.ValidateResponseB3Z1github.com/unarya/unarya/lib/proto/pb/collectorpbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0f\x63ollector.proto\x12\x0b\x63ollectorpb\"\x96\x02\n\nGitRequest\x12\x0b\n\x03url\x18\x01 \x01(\t\x12\x0e\n\x06\x62ranch\x18\x02 \x01(\t\x12\r\n\x05token\x18\x03 \x01(\t\x12\x0e\n\x06\x63ommit\x18\x04 \x01(\t\x12\x0b\n\x03tag\x18\x05 \x01(\t\x12\x0f\n\x07subpath\x18\x06 \x01(\t\x12\r\n\x05\x64\x65pth\x18\x07 \x01(\x05\x12\x12\n\nsubmodules\x18\x08 \x01(\x08\x12\x0b\n\x03lfs\x18\t \x01(\x08\x12\x17\n\x0fssh_private_key\x18\n \x01(\t\x12\x17\n\x0fssh_known_hosts\x18\x0b \x01(\t\x12\x13\n\x0bssh_key_ref\x18\x0c \x01(\t\x12\x0e\n\x06job_id\x18\r \x01(\t\x12\x10\n\x08\x62\x61se_ref\x18\x0e \x01(\t\x12\x15\n\rhistory_depth\x18\x0f \x01(\x05\"S\n\x0e\x41rchiveRequest\x12\x0b\n\x03url\x18\x01 \x01(\t\x12\x14\n\x0cnested_depth\x18\x02 \x01(\x05\x12\x0e\n\x06sha256\x18\x03 \x01(\t\x12\x0e\n\x06job_id\x18\x04 \x01(\t\"9\n\nURLRequest\x12\x0b\n\x03url\x18\x01 \x01(\t\x12\x0e\n\x06sha256\x18\x02 \x01(\t\x12\x0e\n\x06job_id\x18\x03 \x01(\t\">\n\x0cLocalRequest\x12\x0c\n\x04path\x18\x01 \x01(\t\x12\x10\n\x08snapshot\x18\x02 \x01(\x08\x12\x0e\n\x06job_id\x18\x03 \x01(\t\"R\n\x0ePackageRequest\x12\x11\n\tecosystem\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0f\n\x07version\x18\x03 \x01(\t\x12\x0e\n\x06job_id\x18\x04 \x01(\t\";\n\x0cImageRequest\x12\x0b\n\x03url\x18\x01 \x01(\t\x12\x0e\n\x06sha256\x18\x02 \x01(\t\x12\x0e\n\x06job_id\x18\x03 \x01(\t\"\xa2\x02\n\x0e\x43ollectRequest\x12&\n\x03git\x18\x01 \x01(\x0b\x32\x17.collectorpb.GitRequestH\x00\x12.\n\x07\x61rchive\x18\x02 \x01(\x0b\x32\x1b.collectorpb.ArchiveRequestH\x00\x12&\n\x03url\x18\x03 \x01(\x0b\x32\x17.collectorpb.URLRequestH\x00\x12*\n\x05local\x18\x04 \x01(\x0b\x32\x19.collectorpb.LocalRequestH\x00\x12.\n\x07package\x18\x05 \x01(\x0b\x32\x1b.collectorpb.PackageRequestH\x00\x12*\n\x05image\x18\x06 \x01(\x0b\x32\x19.collectorpb.ImageRequestH\x00\x42\x08\n\x06source\"{\n\x0c\x43ollectEvent\x12\x30\n\x08progress\x18\x01 \x01(\x0b\x32\x1c.collectorpb.CollectProgressH\x00\x12\x30\n\x06result\x18\x02 \x01(\x0b\x32\x1e.collectorpb.CollectorResponseH\x00\x42\x07\n\x05\x65vent\"v\n\x0f\x43ollectProgress\x12\r\n\x05stage\x18\x01 \x01(\t\x12\x0c\n\x04step\x18\x02 \x01(\t\x12\x0f\n\x07percent\x18\x03 \x01(\x05\x12\x0c\n\x04\x64one\x18\x04 \x01(\x03\x12\r\n\x05total\x18\x05 \x01(\x03\x12\x18\n\x10\x62ytes_per_second\x18\x06 \x01(\x03\"V\n\x0eUploadMetadata\x12\x0e\n\x06\x66ormat\x18\x01 \x01(\t\x12\x0e\n\x06sha256\x18\x02 \x01(\t\x12\x14\n\x0cnested_depth\x18\x03 \x01(\x05\x12\x0e\n\x06job_id\x18\x04 \x01(\t\"J\n\x0bUploadChunk\x12-\n\x08metadata\x18\x01 \x01(\x0b\x32\x1b.collectorpb.UploadMetadata\x12\x0c\n\x04\x64\x61ta\x18\x02 \x01(\x0c\"\xf9\x01\n\x0fValidateRequest\x12\x0b\n\x03url\x18\x01 \x01(\t\x12\x0c\n\x04type\x18\x02 \x01(\t\x12\x0e\n\x06\x62ranch\x18\x03 \x01(\t\x12\r\n\x05token\x18\x04 \x01(\t\x12\x0e\n\x06\x63ommit\x18\x05 \x01(\t\x12\x0b\n\x03tag\x18\x06 \x01(\t\x12\x17\n\x0fssh_private_key\x18\x07 \x01(\t\x12\x17\n\x0fssh_known_hosts\x18\x08 \x01(\t\x12\x13\n\x0bssh_key_ref\x18\t \x01(\t\x12\x19\n\x11package_ecosystem\x18\n \x01(\t\x12\x14\n\x0cpackage_name\x18\x0b \x01(\t\x12\x17\n\x0fpackage_version\x18\x0c \x01(\t\"i\n\x10ValidateResponse\x12\r\n\x05valid\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x17\n\x0fresolved_commit\x18\x03 \x01(\t\x12\x1c\n\x14\x65stimated_size_bytes\x18\x04 \x01(\x03\"\x80\x04\n\x11\x43ollectorResponse\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x0c\n\x04path\x18\x02 \x01(\t\x12\x13\n\x0brepo_config\x18\x03 \x01(\t\x12\x17\n\x0fresolved_commit\x18\x04 \x01(\t\x12\x14\n\x0clfs_pointers\x18\x05 \x03(\t\x12\x14\n\x0cworkspace_id\x18\x06 \x01(\t\x12\x17\n\x0f\x61rtifact_digest\x18\x07 \x01(\t\x12@\n\tlanguages\x18\x08 \x03(\x0b\x32-.collectorpb.CollectorResponse.LanguagesEntry\x12,\n\x08manifest\x18\t \x03(\x0b\x32\x1a.collectorpb.ManifestEntry\x12+\n\nprovenance\x18\n \x01(\x0b\x32\x17.collectorpb.Provenance\x12\x13\n\x0bmerkle_root\x18\x0b \x01(\t\x12\'\n\x07\x63hanges\x18\x0c \x01(\x0b\x32\x16.collectorpb.ChangeSet\x12%\n\x07history\x18\r \x01(\x0b\x32\x14.collectorpb.History\x12%\n\x05image\x18\x0e \x01(\x0b\x32\x16.collectorpb.ImageInfo\x1a\x30\n\x0eLanguagesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x03:\x02\x38\x01\"\xd7\x02\n\tImageInfo\x12\x11\n\trepo_tags\x18\x01 \x03(\t\x12\n\n\x02os\x18\x02 \x01(\t\x12\x14\n\x0c\x61rchitecture\x18\x03 \x01(\t\x12\x12\n\nentrypoint\x18\x04 \x03(\t\x12\x0b\n\x03\x63md\x18\x05 \x03(\t\x12\x0b\n\x03\x65nv\x18\x06 \x03(\t\x12\x15\n\rexposed_ports\x18\x07 \x03(\t\x12\x0c\n\x04user\x18\x08 \x01(\t\x12\x13\n\x0bworking_dir\x18\t \x01(\t\x12\x32\n\x06labels\x18\n \x03(\x0b\x32\".collectorpb.ImageInfo.LabelsEntry\x12\x0e\n\x06layers\x18\x0b \x01(\x05\x12(\n\x08packages\x18\x0c \x03(\x0b\x32\x16.collectorpb.OSPackage\x12\x10\n\x08warnings\x18\r \x03(\t\x1a-\n\x0bLabelsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"a\n\tOSPackage\x12\x0f\n\x07manager\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0f\n\x07version\x18\x03 \x01(\t\x12\x14\n\x0c\x61rchitecture\x18\x04 \x01(\t\x12\x0e\n\x06source\x18\x05 \x01(\t\"C\n\x07History\x12\x0f\n\x07\x63ommits\x18\x01 \x01(\x05\x12\'\n\x05\x66iles\x18\x02 \x03(\x0b\x32\x18.collectorpb.FileHistory\"\x95\x01\n\x0b\x46ileHistory\x12\x0c\n\x04path\x18\x01 \x01(\t\x12\x0f\n\x07\x63ommits\x18\x02 \x01(\x05\x12\x13\n\x0blines_added\x18\x03 \x01(\x03\x12\x15\n\rlines_removed\x18\x04 \x01(\x03\x12\x0f\n\x07\x61uthors\x18\x05 \x01(\x05\x12\x15\n\rlast_modified\x18\x06 \x01(\t\x12\x13\n\x0blast_commit\x18\x07 \x01(\t\"\x84\x01\n\tChangeSet\x12\x10\n\x08\x62\x61se_ref\x18\x01 \x01(\t\x12\x13\n\x0b\x62\x61se_commit\x18\x02 \x01(\t\x12\x13\n\x0bhead_commit\x18\x03 \x01(\t\x12\x12\n\nmerge_base\x18\x04 \x01(\t\x12\'\n\x05\x66iles\x18\x05 \x03(\x0b\x32\x18.collectorpb.ChangedFile\"o\n\x0b\x43hangedFile\x12\x0c\n\x04path\x18\x01 \x01(\t\x12\x10\n\x08old_path\x18\x02 \x01(\t\x12\x0e\n\x06status\x18\x03 \x01(\t\x12\x0e\n\x06\x62inary\x18\x04 \x01(\x08\x12 \n\x05hunks\x18\x05 \x03(\x0b\x32\x11.collectorpb.Hunk\"R\n\x04Hunk\x12\x11\n\told_start\x18\x01 \x01(\x05\x12\x11\n\told_lines\x18\x02 \x01(\x05\x12\x11\n\tnew_start\x18\x03 \x01(\x05\x12\x11\n\tnew_lines\x18\x04 \x01(\x05\"\\\n\rManifestEntry\x12\x0c\n\x04path\x18\x01 \x01(\t\x12\x0c\n\x04size\x18\x02 \x01(\x03\x12\x0e\n\x06sha256\x18\x03 \x01(\t\x12\r\n\x05\x63lass\x18\x04 \x01(\t\x12\x10\n\x08language\x18\x05 \x01(\t\"w\n\nProvenance\x12\x13\n\x0bsource_type\x18\x01 \x01(\t\x12\x0b\n\x03url\x18\x02 \x01(\t\x12\x0e\n\x06\x63ommit\x18\x03 \x01(\t\x12\x0e\n\x06\x61uthor\x18\x04 \x01(\t\x12\x13\n\x0b\x63ommit_time\x18\x05 \x01(\t\x12\x12\n\nfetched_at\x18\x06 \x01(\t\"?\n\x17\x41\x63quireWorkspaceRequest\x12\x14\n\x0cworkspace_id\x18\x01 \x01(\t\x12\x0e\n\x06job_id\x18\x02 \x01(\t\"?\n\x17ReleaseWorkspaceRequest\x12\x14\n\x0cworkspace_id\x18\x01 \x01(\t\x12\x0e\n\x06job_id\x18\x02 \x01(\t\"C\n\x18ReleaseWorkspaceResponse\x12\x0f\n\x07removed\x18\x01 \x01(\x08\x12\x16\n\x0eremaining_refs\x18\x02 \x01(\x05\"\'\n\x15ListWorkspacesRequest\x12\x0e\n\x06job_id\x18\x01 \x01(\t\"\xc1\x01\n\rWorkspaceInfo\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04path\x18\x02 \x01(\t\x12\x0c\n\x04root\x18\x03 \x01(\t\x12\x13\n\x0bsource_type\x18\x04 \x01(\t\x12\x0c\n\x04jobs\x18\x05 \x03(\t\x12\x12\n\nsize_bytes\x18\x06 \x01(\x03\x12\r\n\x05ready\x18\x07 \x01(\x08\x12\x14\n\x0c\x63reated_unix\x18\x08 \x01(\x03\x12\x16\n\x0elast_used_unix\x18\t \x01(\x03\x12\x14\n\x0c\x65xpires_unix\x18\n \x01(\x03\"h\n\rWorkspaceList\x12.\n\nworkspaces\x18\x01 \x03(\x0b\x32\x1a.collectorpb.WorkspaceInfo\x12\x12\n\nused_bytes\x18\x02 \x01(\x03\x12\x13\n\x0bquota_bytes\x18\x03 \x01(\x03\x32\xdf\x07\n\x10\x43ollectorService\x12I\n\x0e\x43ollectFromGit\x12\x17.collectorpb.GitRequest\x1a\x1e.collectorpb.CollectorResponse\x12Q\n\x12\x43ollectFromArchive\x12\x1b.collectorpb.ArchiveRequest\x1a\x1e.collectorpb.CollectorResponse\x12I\n\x0e\x43ollectFromURL\x12\x17.collectorpb.URLRequest\x1a\x1e.collectorpb.CollectorResponse\x12M\n\x10\x43ollectFromLocal\x12\x19.collectorpb.LocalRequest\x1a\x1e.collectorpb.CollectorResponse\x12Q\n\x12\x43ollectFromPackage\x12\x1b.collectorpb.PackageRequest\x1a\x1e.collectorpb.CollectorResponse\x12M\n\x10\x43ollectFromImage\x12\x19.collectorpb.ImageRequest\x1a\x1e.collectorpb.CollectorResponse\x12I\n\rCollectStream\x12\x1b.collectorpb.CollectRequest\x1a\x19.collectorpb.CollectEvent0\x01\x12J\n\x0cUploadSource\x12\x18.collectorpb.UploadChunk\x1a\x1e.collectorpb.CollectorResponse(\x01\x12M\n\x0eValidateSource\x12\x1c.collectorpb.ValidateRequest\x1a\x1d.collectorpb.ValidateResponse\x12X\n\x10\x41\x63quireWorkspace\x12$.collectorpb.AcquireWorkspaceRequest\x1a\x1e.collectorpb.CollectorResponse\x12_\n\x10ReleaseWorkspace\x12$.collectorpb.ReleaseWorkspaceRequest\x1a%.collectorpb.ReleaseWorkspaceResponse\x12P\n\x0eListWorkspaces\x12\".collectorpb.ListWorkspacesRequest\x1a\x1a.collectorpb.WorkspaceListB3Z1github.com/unarya/unarya/lib/proto/pb/collectorpbb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -32,18 +32,68 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'collector_pb2', _globals)
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z1github.com/unarya/unarya/lib/proto/pb/collectorpb'
-  _globals['_GITREQUEST']._serialized_start=32
-  _globals['_GITREQUEST']._serialized_end=88
-  _globals['_ARCHIVEREQUEST']._serialized_start=90
-  _globals['_ARCHIVEREQUEST']._serialized_end=119
-  _globals['_URLREQUEST']._serialized_start=121
-  _globals['_URLREQUEST']._serialized_end=146
-  _globals['_VALIDATEREQUEST']._serialized_start=148
-  _globals['_VALIDATEREQUEST']._serialized_end=178
-  _globals['_VALIDATERESPONSE']._serialized_start=180
-  _globals['_VALIDATERESPONSE']._serialized_end=230
-  _globals['_COLLECTORRESPONSE']._serialized_start=232
-  _globals['_COLLECTORRESPONSE']._serialized_end=282
-  _globals['_COLLECTORSERVICE']._serialized_start=285
-  _globals['_COLLECTORSERVICE']._serialized_end=615
+  _globals['_GITREQUEST']._serialized_start=33
+  _globals['_GITREQUEST']._serialized_end=311
+  _globals['_ARCHIVEREQUEST']._serialized_start=313
+  _globals['_ARCHIVEREQUEST']._serialized_end=396
+  _globals['_URLREQUEST']._serialized_start=398
+  _globals['_URLREQUEST']._serialized_end=455
+  _globals['_LOCALREQUEST']._serialized_start=457
+  _globals['_LOCALREQUEST']._serialized_end=519
+  _globals['_PACKAGEREQUEST']._serialized_start=521
+  _globals['_PACKAGEREQUEST']._serialized_end=603
+  _globals['_IMAGEREQUEST']._serialized_start=605
+  _globals['_IMAGEREQUEST']._serialized_end=664
+  _globals['_COLLECTREQUEST']._serialized_start=667
+  _globals['_COLLECTREQUEST']._serialized_end=957
+  _globals['_COLLECTEVENT']._serialized_start=959
+  _globals['_COLLECTEVENT']._serialized_end=1082
+  _globals['_COLLECTPROGRESS']._serialized_start=1084
+  _globals['_COLLECTPROGRESS']._serialized_end=1202
+  _globals['_UPLOADMETADATA']._serialized_start=1204
+  _globals['_UPLOADMETADATA']._serialized_end=1290
+  _globals['_UPLOADCHUNK']._serialized_start=1292
+  _globals['_UPLOADCHUNK']._serialized_end=1366
+  _globals['_VALIDATEREQUEST']._serialized_start=1369
+  _globals['_VALIDATEREQUEST']._serialized_end=1618
+  _globals['_VALIDATERESPONSE']._serialized_start=1620
+  _globals['_VALIDATERESPONSE']._serialized_end=1725
+  _globals['_COLLECTORRESPONSE']._serialized_start=1728
+  _globals['_COLLECTORRESPONSE']._serialized_end=2240
+  _globals['_COLLECTORRESPONSE_LANGUAGESENTRY']._serialized_start=2192
+  _globals['_COLLECTORRESPONSE_LANGUAGESENTRY']._serialized_end=2240
+  _globals['_IMAGEINFO']._serialized_start=2243
+  _globals['_IMAGEINFO']._serialized_end=2586
+  _globals['_IMAGEINFO_LABELSENTRY']._serialized_start=2541
+  _globals['_IMAGEINFO_LABELSENTRY']._serialized_end=2586
+  _globals['_OSPACKAGE']._serialized_start=2588
+  _globals['_OSPACKAGE']._serialized_end=2685
+  _globals['_HISTORY']._serialized_start=2687
+  _globals['_HISTORY']._serialized_end=2754
+  _globals['_FILEHISTORY']._serialized_start=2757
+  _globals['_FILEHISTORY']._serialized_end=2906
+  _globals['_CHANGESET']._serialized_start=2909
+  _globals['_CHANGESET']._serialized_end=3041
+  _globals['_CHANGEDFILE']._serialized_start=3043
+  _globals['_CHANGEDFILE']._serialized_end=3154
+  _globals['_HUNK']._serialized_start=3156
+  _globals['_HUNK']._serialized_end=3238
+  _globals['_MANIFESTENTRY']._serialized_start=3240
+  _globals['_MANIFESTENTRY']._serialized_end=3332
+  _globals['_PROVENANCE']._serialized_start=3334
+  _globals['_PROVENANCE']._serialized_end=3453
+  _globals['_ACQUIREWORKSPACEREQUEST']._serialized_start=3455
+  _globals['_ACQUIREWORKSPACEREQUEST']._serialized_end=3518
+  _globals['_RELEASEWORKSPACEREQUEST']._serialized_start=3520
+  _globals['_RELEASEWORKSPACEREQUEST']._serialized_end=3583
+  _globals['_RELEASEWORKSPACERESPONSE']._serialized_start=3585
+  _globals['_RELEASEWORKSPACERESPONSE']._serialized_end=3652
+  _globals['_LISTWORKSPACESREQUEST']._serialized_start=3654
+  _globals['_LISTWORKSPACESREQUEST']._serialized_end=3693
+  _globals['_WORKSPACEINFO']._serialized_start=3696
+  _globals['_WORKSPACEINFO']._serialized_end=3889
+  _globals['_WORKSPACELIST']._serialized_start=3891
+  _globals['_WORKSPACELIST']._serialized_end=3995
+  _globals['_COLLECTORSERVICE']._serialized_start=3998
+  _globals['_COLLECTORSERVICE']._serialized_end=4989
 # @@protoc_insertion_point(module_scope)
@@ -51,11 +51,51 @@ class CollectorServiceStub(object):
                 request_serializer=collector__pb2.URLRequest.SerializeToString,
                 response_deserializer=collector__pb2.CollectorResponse.FromString,
                 _registered_method=True)
+        self.CollectFromLocal = channel.unary_unary(
+                '/collectorpb.CollectorService/CollectFromLocal',
+                request_serializer=collector__pb2.LocalRequest.SerializeToString,
+                response_deserializer=collector__pb2.CollectorResponse.FromString,
+                _registered_method=True)
+        self.CollectFromPackage = channel.unary_unary(
+                '/collectorpb.CollectorService/CollectFromPackage',
+                request_serializer=collector__pb2.PackageRequest.SerializeToString,
+                response_deserializer=collector__pb2.CollectorResponse.FromString,
+                _registered_method=True)
+        self.CollectFromImage = channel.unary_unary(
+                '/collectorpb.CollectorService/CollectFromImage',
+                request_serializer=collector__pb2.ImageRequest.SerializeToString,
+                response_deserializer=collector__pb2.CollectorResponse.FromString,
+                _registered_method=True)
+        self.CollectStream = channel.unary_stream(
+                '/collectorpb.CollectorService/CollectStream',
+                request_serializer=collector__pb2.CollectRequest.SerializeToString,
+                response_deserializer=collector__pb2.CollectEvent.FromString,
+                _registered_method=True)
+        self.UploadSource = channel.stream_unary(
+                '/collectorpb.CollectorService/UploadSource',
+                request_serializer=collector__pb2.UploadChunk.SerializeToString,
+                response_deserializer=collector__pb2.CollectorResponse.FromString,
+                _registered_method=True)
         self.ValidateSource = channel.unary_unary(
                 '/collectorpb.CollectorService/ValidateSource',
                 request_serializer=collector__pb2.ValidateRequest.SerializeToString,
                 response_deserializer=collector__pb2.ValidateResponse.FromString,
                 _registered_method=True)
+        self.AcquireWorkspace = channel.unary_unary(
+                '/collectorpb.CollectorService/AcquireWorkspace',
+                request_serializer=collector__pb2.AcquireWorkspaceRequest.SerializeToString,
+                response_deserializer=collector__pb2.CollectorResponse.FromString,
+                _registered_method=True)
+        self.ReleaseWorkspace = channel.unary_unary(
+                '/collectorpb.CollectorService/ReleaseWorkspace',
+                request_serializer=collector__pb2.ReleaseWorkspaceRequest.SerializeToString,
+                response_deserializer=collector__pb2.ReleaseWorkspaceResponse.FromString,
+                _registered_method=True)
+        self.ListWorkspaces = channel.unary_unary(
+                '/collectorpb.CollectorService/ListWorkspaces',
+                request_serializer=collector__pb2.ListWorkspacesRequest.SerializeToString,
+                response_deserializer=collector__pb2.WorkspaceList.FromString,
+                _registered_method=True)
 
 
 class CollectorServiceServicer(object):
@@ -84,6 +124,45 @@ class CollectorServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def CollectFromLocal(self, request, context):
+        """Collect a directory already on the collector's filesystem
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CollectFromPackage(self, request, context):
+        """Download a package version from the Go proxy, npm or PyPI and verify
+        the checksums the registry publishes
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CollectFromImage(self, request, context):
+        """Download a docker save or OCI layout tarball, flatten its layers and
+        inventory its OS packages
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CollectStream(self, request, context):
+        """Collect any source like the unary RPCs above, streaming progress
+        events and then the CollectorResponse
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def UploadSource(self, request_iterator, context):
+        """Receive an archive pushed by the client in chunks. The first message
+        carries the metadata; every message may carry data.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def ValidateSource(self, request, context):
         """Validate incoming source URL
         """
@@ -91,6 +170,27 @@ class CollectorServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def AcquireWorkspace(self, request, context):
+        """Add a job's reference to a finished workspace and describe its collection
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ReleaseWorkspace(self, request, context):
+        """Drop a job's reference to a workspace; it is deleted with the last one
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListWorkspaces(self, request, context):
+        """List workspaces and disk usage
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_CollectorServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -109,11 +209,51 @@ def add_CollectorServiceServicer_to_server(servicer, server):
                     request_deserializer=collector__pb2.URLRequest.FromString,
                     response_serializer=collector__pb2.CollectorResponse.SerializeToString,
             ),
+            'CollectFromLocal': grpc.unary_unary_rpc_method_handler(
+                    servicer.CollectFromLocal,
+                    request_deserializer=collector__pb2.LocalRequest.FromString,
+                    response_serializer=collector__pb2.CollectorResponse.SerializeToString,
+            ),
+            'CollectFromPackage': grpc.unary_unary_rpc_method_handler(
+                    servicer.CollectFromPackage,
+                    request_deserializer=collector__pb2.PackageRequest.FromString,
+                    response_serializer=collector__pb2.CollectorResponse.SerializeToString,
+            ),
+            'CollectFromImage': grpc.unary_unary_rpc_method_handler(
+                    servicer.CollectFromImage,
+                    request_deserializer=collector__pb2.ImageRequest.FromString,
+                    response_serializer=collector__pb2.CollectorResponse.SerializeToString,
+            ),
+            'CollectStream': grpc.unary_stream_rpc_method_handler(
+                    servicer.CollectStream,
+                    request_deserializer=collector__pb2.CollectRequest.FromString,
+                    response_serializer=collector__pb2.CollectEvent.SerializeToString,
+            ),
+            'UploadSource': grpc.stream_unary_rpc_method_handler(
+                    servicer.UploadSource,
+                    request_deserializer=collector__pb2.UploadChunk.FromString,
+                    response_serializer=collector__pb2.CollectorResponse.SerializeToString,
+            ),
             'ValidateSource': grpc.unary_unary_rpc_method_handler(
                     servicer.ValidateSource,
                     request_deserializer=collector__pb2.ValidateRequest.FromString,
                     response_serializer=collector__pb2.ValidateResponse.SerializeToString,
             ),
+            'AcquireWorkspace': grpc.unary_unary_rpc_method_handler(
+                    servicer.AcquireWorkspace,
+                    request_deserializer=collector__pb2.AcquireWorkspaceRequest.FromString,
+                    response_serializer=collector__pb2.CollectorResponse.SerializeToString,
+            ),
+            'ReleaseWorkspace': grpc.unary_unary_rpc_method_handler(
+                    servicer.ReleaseWorkspace,
+                    request_deserializer=collector__pb2.ReleaseWorkspaceRequest.FromString,
+                    response_serializer=collector__pb2.ReleaseWorkspaceResponse.SerializeToString,
+            ),
+            'ListWorkspaces': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListWorkspaces,
+                    request_deserializer=collector__pb2.ListWorkspacesRequest.FromString,
+                    response_serializer=collector__pb2.WorkspaceList.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'collectorpb.CollectorService', rpc_method_handlers)
@@ -208,6 +348,141 @@ class CollectorService(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def CollectFromLocal(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/collectorpb.CollectorService/CollectFromLocal',
+            collector__pb2.LocalRequest.SerializeToString,
+            collector__pb2.CollectorResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def CollectFromPackage(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/collectorpb.CollectorService/CollectFromPackage',
+            collector__pb2.PackageRequest.SerializeToString,
+            collector__pb2.CollectorResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def CollectFromImage(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/collectorpb.CollectorService/CollectFromImage',
+            collector__pb2.ImageRequest.SerializeToString,
+            collector__pb2.CollectorResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def CollectStream(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_stream(
+            request,
+            target,
+            '/collectorpb.CollectorService/CollectStream',
+            collector__pb2.CollectRequest.SerializeToString,
+            collector__pb2.CollectEvent.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def UploadSource(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_unary(
+            request_iterator,
+            target,
+            '/collectorpb.CollectorService/UploadSource',
+            collector__pb2.UploadChunk.SerializeToString,
+            collector__pb2.CollectorResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
     @staticmethod
     def ValidateSource(request,
             target,
@@ -234,3 +509,84 @@ class CollectorService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def AcquireWorkspace(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/collectorpb.CollectorService/AcquireWorkspace',
+            collector__pb2.AcquireWorkspaceRequest.SerializeToString,
+            collector__pb2.CollectorResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ReleaseWorkspace(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/collectorpb.CollectorService/ReleaseWorkspace',
+            collector__pb2.ReleaseWorkspaceRequest.SerializeToString,
+            collector__pb2.ReleaseWorkspaceResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ListWorkspaces(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/collectorpb.CollectorService/ListWorkspaces',
+            collector__pb2.ListWorkspacesRequest.SerializeToString,
+            collector__pb2.WorkspaceList.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
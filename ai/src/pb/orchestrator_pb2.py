@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x12orchestrator.proto\x12\x0eorchestratorpb\")\n\x0fPipelineRequest\x12\x16\n\x0erepository_url\x18\x01 \x01(\t\"3\n\x10PipelineResponse\x12\x0e\n\x06status\x18\x01 \x01(\t\x12\x0f\n\x07\x64\x65tails\x18\x02 \x01(\t2i\n\x13OrchestratorService\x12R\n\rStartPipeline\x12\x1f.orchestratorpb.PipelineRequest\x1a .orchestratorpb.PipelineResponseB6Z4github.com/unarya/unarya/lib/proto/pb/orchestratorpbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x12orchestrator.proto\x12\x0eorchestratorpb\"\xe5\x03\n\x0fPipelineRequest\x12\x16\n\x0erepository_url\x18\x01 \x01(\t\x12\x0e\n\x06\x62ranch\x18\x02 \x01(\t\x12\r\n\x05token\x18\x03 \x01(\t\x12\x13\n\x0bsource_type\x18\x04 \x01(\t\x12\x10\n\x08template\x18\x05 \x01(\t\x12\x14\n\x0cnested_depth\x18\x06 \x01(\x05\x12\x10\n\x08snapshot\x18\x07 \x01(\x08\x12\x0e\n\x06\x63ommit\x18\x08 \x01(\t\x12\x0b\n\x03tag\x18\t \x01(\t\x12\x0f\n\x07subpath\x18\n \x01(\t\x12\r\n\x05\x64\x65pth\x18\x0b \x01(\x05\x12\x12\n\nsubmodules\x18\x0c \x01(\x08\x12\x0b\n\x03lfs\x18\r \x01(\x08\x12\x17\n\x0fssh_private_key\x18\x0e \x01(\t\x12\x17\n\x0fssh_known_hosts\x18\x0f \x01(\t\x12\x13\n\x0bssh_key_ref\x18\x10 \x01(\t\x12\x0e\n\x06sha256\x18\x11 \x01(\t\x12\x10\n\x08\x62\x61se_ref\x18\x12 \x01(\t\x12\x15\n\rhistory_depth\x18\x13 \x01(\x05\x12\x19\n\x11package_ecosystem\x18\x14 \x01(\t\x12\x14\n\x0cpackage_name\x18\x15 \x01(\t\x12\x17\n\x0fpackage_version\x18\x16 \x01(\t\x12\x0e\n\x06job_id\x18\x17 \x01(\t\x12\x14\n\x0cworkspace_id\x18\x18 \x01(\t\"\x88\x01\n\x10PipelineResponse\x12\x0e\n\x06status\x18\x01 \x01(\t\x12\x0f\n\x07\x64\x65tails\x18\x02 \x01(\t\x12\x0e\n\x06job_id\x18\x03 \x01(\t\x12\x15\n\rconfig_errors\x18\x04 \x03(\t\x12\x17\n\x0fresolved_commit\x18\x05 \x01(\t\x12\x13\n\x0bmerkle_root\x18\x06 \x01(\t\"\xa4\x01\n\x0cPipelinePlan\x12\r\n\x05valid\x18\x01 \x01(\x08\x12\x10\n\x08template\x18\x02 \x01(\t\x12,\n\x06stages\x18\x03 \x03(\x0b\x32\x1c.orchestratorpb.PlannedStage\x12\x17\n\x0fresolved_commit\x18\x04 \x01(\t\x12\x1c\n\x14\x65stimated_size_bytes\x18\x05 \x01(\x03\x12\x0e\n\x06\x65rrors\x18\x06 \x03(\t\"N\n\x0cPlannedStage\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0e\n\x06target\x18\x02 \x01(\t\x12\x11\n\treachable\x18\x03 \x01(\x08\x12\r\n\x05\x65rror\x18\x04 \x01(\t\"!\n\x0fWatchJobRequest\x12\x0e\n\x06job_id\x18\x01 \x01(\t\"\x87\x01\n\x08JobEvent\x12\x0e\n\x06job_id\x18\x01 \x01(\t\x12\x0e\n\x06status\x18\x02 \x01(\t\x12\r\n\x05stage\x18\x03 \x01(\t\x12\x0f\n\x07\x64\x65tails\x18\x04 \x01(\t\x12\x0c\n\x04time\x18\x05 \x01(\t\x12-\n\x08progress\x18\x06 \x01(\x0b\x32\x1b.orchestratorpb.JobProgress\"r\n\x0bJobProgress\x12\r\n\x05stage\x18\x01 \x01(\t\x12\x0c\n\x04step\x18\x02 \x01(\t\x12\x0f\n\x07percent\x18\x03 \x01(\x05\x12\x0c\n\x04\x64one\x18\x04 \x01(\x03\x12\r\n\x05total\x18\x05 \x01(\x03\x12\x18\n\x10\x62ytes_per_second\x18\x06 \x01(\x03\x32\x85\x02\n\x13OrchestratorService\x12R\n\rStartPipeline\x12\x1f.orchestratorpb.PipelineRequest\x1a .orchestratorpb.PipelineResponse\x12Q\n\x10ValidatePipeline\x12\x1f.orchestratorpb.PipelineRequest\x1a\x1c.orchestratorpb.PipelinePlan\x12G\n\x08WatchJob\x12\x1f.orchestratorpb.WatchJobRequest\x1a\x18.orchestratorpb.JobEvent0\x01\x42\x36Z4github.com/unarya/unarya/lib/proto/pb/orchestratorpbb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -32,10 +32,20 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'orchestrator_pb2', _globals
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z4github.com/unarya/unarya/lib/proto/pb/orchestratorpb'
-  _globals['_PIPELINEREQUEST']._serialized_start=38
-  _globals['_PIPELINEREQUEST']._serialized_end=79
-  _globals['_PIPELINERESPONSE']._serialized_start=81
-  _globals['_PIPELINERESPONSE']._serialized_end=132
-  _globals['_ORCHESTRATORSERVICE']._serialized_start=134
-  _globals['_ORCHESTRATORSERVICE']._serialized_end=239
+  _globals['_PIPELINEREQUEST']._serialized_start=39
+  _globals['_PIPELINEREQUEST']._serialized_end=524
+  _globals['_PIPELINERESPONSE']._serialized_start=527
+  _globals['_PIPELINERESPONSE']._serialized_end=663
+  _globals['_PIPELINEPLAN']._serialized_start=666
+  _globals['_PIPELINEPLAN']._serialized_end=830
+  _globals['_PLANNEDSTAGE']._serialized_start=832
+  _globals['_PLANNEDSTAGE']._serialized_end=910
+  _globals['_WATCHJOBREQUEST']._serialized_start=912
+  _globals['_WATCHJOBREQUEST']._serialized_end=945
+  _globals['_JOBEVENT']._serialized_start=948
+  _globals['_JOBEVENT']._serialized_end=1083
+  _globals['_JOBPROGRESS']._serialized_start=1085
+  _globals['_JOBPROGRESS']._serialized_end=1199
+  _globals['_ORCHESTRATORSERVICE']._serialized_start=1202
+  _globals['_ORCHESTRATORSERVICE']._serialized_end=1463
 # @@protoc_insertion_point(module_scope)
@@ -41,6 +41,16 @@ class OrchestratorServiceStub(object):
                 request_serializer=orchestrator__pb2.PipelineRequest.SerializeToString,
                 response_deserializer=orchestrator__pb2.PipelineResponse.FromString,
                 _registered_method=True)
+        self.ValidatePipeline = channel.unary_unary(
+                '/orchestratorpb.OrchestratorService/ValidatePipeline',
+                request_serializer=orchestrator__pb2.PipelineRequest.SerializeToString,
+                response_deserializer=orchestrator__pb2.PipelinePlan.FromString,
+                _registered_method=True)
+        self.WatchJob = channel.unary_stream(
+                '/orchestratorpb.OrchestratorService/WatchJob',
+                request_serializer=orchestrator__pb2.WatchJobRequest.SerializeToString,
+                response_deserializer=orchestrator__pb2.JobEvent.FromString,
+                _registered_method=True)
 
 
 class OrchestratorServiceServicer(object):
@@ -54,6 +64,21 @@ class OrchestratorServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def ValidatePipeline(self, request, context):
+        """Check that a pipeline request would work without collecting anything
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def WatchJob(self, request, context):
+        """Stream a job's status, stage and collection progress until it finishes.
+        The first event is the job's current state.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_OrchestratorServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -62,6 +87,16 @@ def add_OrchestratorServiceServicer_to_server(servicer, server):
                     request_deserializer=orchestrator__pb2.PipelineRequest.FromString,
                     response_serializer=orchestrator__pb2.PipelineResponse.SerializeToString,
             ),
+            'ValidatePipeline': grpc.unary_unary_rpc_method_handler(
+                    servicer.ValidatePipeline,
+                    request_deserializer=orchestrator__pb2.PipelineRequest.FromString,
+                    response_serializer=orchestrator__pb2.PipelinePlan.SerializeToString,
+            ),
+            'WatchJob': grpc.unary_stream_rpc_method_handler(
+                    servicer.WatchJob,
+                    request_deserializer=orchestrator__pb2.WatchJobRequest.FromString,
+                    response_serializer=orchestrator__pb2.JobEvent.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'orchestratorpb.OrchestratorService', rpc_method_handlers)
@@ -101,3 +136,57 @@ class OrchestratorService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def ValidatePipeline(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/orchestratorpb.OrchestratorService/ValidatePipeline',
+            orchestrator__pb2.PipelineRequest.SerializeToString,
+            orchestrator__pb2.PipelinePlan.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def WatchJob(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_stream(
+            request,
+            target,
+            '/orchestratorpb.OrchestratorService/WatchJob',
+            orchestrator__pb2.WatchJobRequest.SerializeToString,
+            orchestrator__pb2.JobEvent.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
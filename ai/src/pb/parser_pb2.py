@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0cparser.proto\x12\x08parserpb\"#\n\x0cParseRequest\x12\x13\n\x0bsource_path\x18\x01 \x01(\t\"g\n\rParseResponse\x12\x10\n\x08language\x18\x01 \x01(\t\x12\x14\n\x0c\x64\x65pendencies\x18\x02 \x03(\t\x12\x16\n\x0e\x63ode_structure\x18\x03 \x01(\t\x12\x16\n\x0erepresentation\x18\x04 \x01(\t2M\n\rParserService\x12<\n\tParseCode\x12\x16.parserpb.ParseRequest\x1a\x17.parserpb.ParseResponseB0Z.github.com/unarya/unarya/lib/proto/pb/parserpbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0cparser.proto\x12\x08parserpb\"e\n\x0cParseRequest\x12\x13\n\x0bsource_path\x18\x01 \x01(\t\x12\x13\n\x0brepo_config\x18\x02 \x01(\t\x12\x17\n\x0f\x61rtifact_digest\x18\x03 \x01(\t\x12\x12\n\nchange_set\x18\x04 \x01(\t\"\x94\x01\n\rParseResponse\x12\x10\n\x08language\x18\x01 \x01(\t\x12\x14\n\x0c\x64\x65pendencies\x18\x02 \x03(\t\x12\x16\n\x0e\x63ode_structure\x18\x03 \x01(\t\x12\x16\n\x0erepresentation\x18\x04 \x01(\t\x12+\n\x0c\x66ile_metrics\x18\x05 \x03(\x0b\x32\x15.parserpb.FileMetrics\"b\n\x0b\x46ileMetrics\x12\x0c\n\x04path\x18\x01 \x01(\t\x12\r\n\x05lines\x18\x02 \x01(\x05\x12\x11\n\tfunctions\x18\x03 \x01(\x05\x12\x0f\n\x07\x63lasses\x18\x04 \x01(\x05\x12\x12\n\ncomplexity\x18\x05 \x01(\x05\x32M\n\rParserService\x12<\n\tParseCode\x12\x16.parserpb.ParseRequest\x1a\x17.parserpb.ParseResponseB0Z.github.com/unarya/unarya/lib/proto/pb/parserpbb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -33,9 +33,11 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z.github.com/unarya/unarya/lib/proto/pb/parserpb'
   _globals['_PARSEREQUEST']._serialized_start=26
-  _globals['_PARSEREQUEST']._serialized_end=61
-  _globals['_PARSERESPONSE']._serialized_start=63
-  _globals['_PARSERESPONSE']._serialized_end=166
-  _globals['_PARSERSERVICE']._serialized_start=168
-  _globals['_PARSERSERVICE']._serialized_end=245
+  _globals['_PARSEREQUEST']._serialized_end=127
+  _globals['_PARSERESPONSE']._serialized_start=130
+  _globals['_PARSERESPONSE']._serialized_end=278
+  _globals['_FILEMETRICS']._serialized_start=280
+  _globals['_FILEMETRICS']._serialized_end=378
+  _globals['_PARSERSERVICE']._serialized_start=380
+  _globals['_PARSERSERVICE']._serialized_end=457
 # @@protoc_insertion_point(module_scope)
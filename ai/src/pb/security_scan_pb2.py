@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x13security_scan.proto\x12\x0esecurityscanpb\"\"\n\x0bScanRequest\x12\x13\n\x0bsource_path\x18\x01 \x01(\t\"3\n\x0cScanResponse\x12\x0e\n\x06report\x18\x01 \x01(\t\x12\x13\n\x0btotal_finds\x18\x02 \x01(\x05\x32j\n\x13SecurityScanService\x12S\n\x16ScanForVulnerabilities\x12\x1b.securityscanpb.ScanRequest\x1a\x1c.securityscanpb.ScanResponseB7Z5github.com/unarya/unarya/lib/proto/pb/security_scanpbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x13security_scan.proto\x12\x0esecurityscanpb\"d\n\x0bScanRequest\x12\x13\n\x0bsource_path\x18\x01 \x01(\t\x12\x13\n\x0brepo_config\x18\x02 \x01(\t\x12\x17\n\x0f\x61rtifact_digest\x18\x03 \x01(\t\x12\x12\n\nchange_set\x18\x04 \x01(\t\"3\n\x0cScanResponse\x12\x0e\n\x06report\x18\x01 \x01(\t\x12\x13\n\x0btotal_finds\x18\x02 \x01(\x05\x32j\n\x13SecurityScanService\x12S\n\x16ScanForVulnerabilities\x12\x1b.securityscanpb.ScanRequest\x1a\x1c.securityscanpb.ScanResponseB7Z5github.com/unarya/unarya/lib/proto/pb/security_scanpbb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -33,9 +33,9 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z5github.com/unarya/unarya/lib/proto/pb/security_scanpb'
   _globals['_SCANREQUEST']._serialized_start=39
-  _globals['_SCANREQUEST']._serialized_end=73
-  _globals['_SCANRESPONSE']._serialized_start=75
-  _globals['_SCANRESPONSE']._serialized_end=126
-  _globals['_SECURITYSCANSERVICE']._serialized_start=128
-  _globals['_SECURITYSCANSERVICE']._serialized_end=234
+  _globals['_SCANREQUEST']._serialized_end=139
+  _globals['_SCANRESPONSE']._serialized_start=141
+  _globals['_SCANRESPONSE']._serialized_end=192
+  _globals['_SECURITYSCANSERVICE']._serialized_start=194
+  _globals['_SECURITYSCANSERVICE']._serialized_end=300
 # @@protoc_insertion_point(module_scope)
@@ -1,4 +1,4 @@
-module ai-runtime
+module github.com/unarya/unarya/cmd/ai-runtime
 
 go 1.25.0
 
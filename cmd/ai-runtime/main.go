@@ -1,16 +1,26 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 
+	"github.com/unarya/unarya/internal/shared/config"
+	sharedgrpc "github.com/unarya/unarya/internal/shared/grpc"
 	"github.com/unarya/unarya/lib/proto/pb/aipb"
-	"google.golang.org/grpc"
 )
 
 func main() {
-	server := grpc.NewServer()
+	cfg := config.Load()
+	intake := sharedgrpc.NewIntake()
+	auditor, err := sharedgrpc.OpenAuditor(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to open audit log: %v", err)
+	}
+	defer auditor.Close()
+
+	server := sharedgrpc.NewServer(intake, auditor, sharedgrpc.NewAdminPolicy(cfg))
 	runtimeSrv := &RuntimeServer{}
 
 	aipb.RegisterAIInferenceServer(server, runtimeSrv)
@@ -26,7 +36,16 @@ func main() {
 	}
 
 	fmt.Println("[AI Runtime] 🚀 Serving on port 6000 (GPU mode)")
-	if err := server.Serve(listener); err != nil {
+	err = sharedgrpc.ServeWithShutdown(server, listener, sharedgrpc.ShutdownOptions{
+		Intake:       intake,
+		DrainTimeout: cfg.DrainTimeout,
+		OnShutdown: []func(context.Context){func(ctx context.Context) {
+			if err := runtimeSrv.Close(); err != nil {
+				log.Printf("[Runtime] Warning: failed to release model: %v", err)
+			}
+		}},
+	})
+	if err != nil {
 		log.Fatalf("gRPC serve error: %v", err)
 	}
 }
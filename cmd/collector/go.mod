@@ -1,10 +1,11 @@
-module collector
+module github.com/unarya/unarya/cmd/collector
 
 go 1.25.0
 
 require (
 	github.com/unarya/unarya v0.11.0-alpha.1
 	google.golang.org/grpc v1.76.0
+	google.golang.org/protobuf v1.36.6
 )
 
 require (
@@ -12,5 +13,4 @@ require (
 	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/text v0.27.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250804133106-a7a43d27e69b // indirect
-	google.golang.org/protobuf v1.36.6 // indirect
 )
@@ -1,25 +1,56 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/unarya/unarya/internal/collector"
+	"github.com/unarya/unarya/internal/shared/artifact"
+	"github.com/unarya/unarya/internal/shared/changeset"
+	"github.com/unarya/unarya/internal/shared/config"
+	sharedgrpc "github.com/unarya/unarya/internal/shared/grpc"
+	"github.com/unarya/unarya/internal/shared/repoconfig"
+	"github.com/unarya/unarya/internal/shared/utils"
 	"github.com/unarya/unarya/lib/proto/pb/collectorpb"
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // CollectorServer implements collectorpb.CollectorServiceServer
 type CollectorServer struct {
 	collectorpb.UnimplementedCollectorServiceServer
+
+	workspaces *collector.WorkspaceManager
+	artifacts  artifact.Store // nil when stages share the workspace volume
+	maxUpload  int64          // bytes accepted by UploadSource
 }
 
+// defaultMaxUploadBytes applies when COLLECTOR_MAX_UPLOAD_BYTES is unset
+const defaultMaxUploadBytes = 1 << 30
+
+// Workspace defaults, overridden by COLLECTOR_WORKSPACE_TTL and
+// COLLECTOR_WORKSPACE_QUOTA_BYTES
+const (
+	defaultWorkspaceTTL   = 24 * time.Hour
+	defaultWorkspaceQuota = 20 << 30
+)
+
+// defaultMirrorQuota applies when COLLECTOR_MIRROR_QUOTA_BYTES is unset
+const defaultMirrorQuota = 10 << 30
+
 // main starts the gRPC Collector service
 func main() {
 	port := os.Getenv("COLLECTOR_PORT")
@@ -32,11 +63,98 @@ func main() {
 		log.Fatalf("Failed to listen on port %s: %v", port, err)
 	}
 
-	s := grpc.NewServer()
-	collectorpb.RegisterCollectorServiceServer(s, &CollectorServer{})
+	cfg := config.Load()
+	collector.AllowLocalRoots(filepath.SplitList(os.Getenv("COLLECTOR_LOCAL_ROOTS"))...)
+	collector.SetSSHKeyDir(os.Getenv("COLLECTOR_SSH_KEY_DIR"))
+	collector.SetGitHosts(cfg.GitAllowedHosts...)
+	if err := collector.AllowPrivateNetworks(cfg.AllowedPrivateNetworks...); err != nil {
+		log.Fatalf("Invalid ALLOWED_PRIVATE_NETWORKS: %v", err)
+	}
+	for ecosystem, registry := range map[string]string{
+		collector.EcosystemGo:   cfg.GoProxyURL,
+		collector.EcosystemNPM:  cfg.NPMRegistryURL,
+		collector.EcosystemPyPI: cfg.PyPIURL,
+	} {
+		if err := collector.SetRegistry(ecosystem, registry); err != nil {
+			log.Fatalf("Invalid package registry: %v", err)
+		}
+	}
+	intake := sharedgrpc.NewIntake()
+	collectorSrv := &CollectorServer{maxUpload: defaultMaxUploadBytes}
+	if v := os.Getenv("COLLECTOR_MAX_UPLOAD_BYTES"); v != "" {
+		if collectorSrv.maxUpload, err = strconv.ParseInt(v, 10, 64); err != nil {
+			log.Fatalf("Invalid COLLECTOR_MAX_UPLOAD_BYTES %q: %v", v, err)
+		}
+	}
+	if v := os.Getenv("COLLECTOR_MAX_DOWNLOAD_BYTES"); v != "" {
+		if collector.DefaultDownloader.MaxBytes, err = strconv.ParseInt(v, 10, 64); err != nil {
+			log.Fatalf("Invalid COLLECTOR_MAX_DOWNLOAD_BYTES %q: %v", v, err)
+		}
+	}
+	if v := os.Getenv("COLLECTOR_DOWNLOAD_TIMEOUT"); v != "" {
+		if collector.DefaultDownloader.Timeout, err = time.ParseDuration(v); err != nil {
+			log.Fatalf("Invalid COLLECTOR_DOWNLOAD_TIMEOUT %q: %v", v, err)
+		}
+	}
+
+	ttl, quota := defaultWorkspaceTTL, int64(defaultWorkspaceQuota)
+	if v := os.Getenv("COLLECTOR_WORKSPACE_TTL"); v != "" {
+		if ttl, err = time.ParseDuration(v); err != nil {
+			log.Fatalf("Invalid COLLECTOR_WORKSPACE_TTL %q: %v", v, err)
+		}
+	}
+	if v := os.Getenv("COLLECTOR_WORKSPACE_QUOTA_BYTES"); v != "" {
+		if quota, err = strconv.ParseInt(v, 10, 64); err != nil {
+			log.Fatalf("Invalid COLLECTOR_WORKSPACE_QUOTA_BYTES %q: %v", v, err)
+		}
+	}
+	workspaceDir := os.Getenv("COLLECTOR_WORKSPACE_DIR")
+	if workspaceDir == "" {
+		workspaceDir = filepath.Join(os.TempDir(), "unarya-workspaces")
+	}
+	if collectorSrv.workspaces, err = collector.NewWorkspaceManager(workspaceDir, ttl, quota); err != nil {
+		log.Fatalf("Failed to open workspace directory %s: %v", workspaceDir, err)
+	}
+	// Git mirrors are opt-in: COLLECTOR_MIRROR_DIR enables them
+	if mirrorDir := os.Getenv("COLLECTOR_MIRROR_DIR"); mirrorDir != "" {
+		mirrorQuota := int64(defaultMirrorQuota)
+		if v := os.Getenv("COLLECTOR_MIRROR_QUOTA_BYTES"); v != "" {
+			if mirrorQuota, err = strconv.ParseInt(v, 10, 64); err != nil {
+				log.Fatalf("Invalid COLLECTOR_MIRROR_QUOTA_BYTES %q: %v", v, err)
+			}
+		}
+		mirrors, err := collector.NewMirrorCache(mirrorDir, mirrorQuota)
+		if err != nil {
+			log.Fatalf("Failed to open mirror directory %s: %v", mirrorDir, err)
+		}
+		collector.SetMirrorCache(mirrors)
+	}
+	gcCtx, stopGC := context.WithCancel(context.Background())
+	defer stopGC()
+	if ttl > 0 {
+		go collectorSrv.workspaces.RunGC(gcCtx, min(ttl/4, 10*time.Minute))
+	}
+
+	if collectorSrv.artifacts, err = artifact.Open(cfg); err != nil {
+		log.Fatalf("❌ Failed to open artifact store: %v", err)
+	}
+
+	auditor, err := sharedgrpc.OpenAuditor(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to open audit log: %v", err)
+	}
+	defer auditor.Close()
+
+	s := sharedgrpc.NewServer(intake, auditor, sharedgrpc.NewAdminPolicy(cfg))
+	collectorpb.RegisterCollectorServiceServer(s, collectorSrv)
 
 	log.Printf("🚀 Collector service started on port %s", port)
-	if err := s.Serve(lis); err != nil {
+	err = sharedgrpc.ServeWithShutdown(s, lis, sharedgrpc.ShutdownOptions{
+		Intake:       intake,
+		DrainTimeout: cfg.DrainTimeout,
+		OnShutdown:   []func(context.Context){collectorSrv.workspaces.RemovePending},
+	})
+	if err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
@@ -44,88 +162,555 @@ func main() {
 // CollectFromGit clones a repository from Git with optional authentication
 func (c *CollectorServer) CollectFromGit(ctx context.Context, req *collectorpb.GitRequest) (*collectorpb.CollectorResponse, error) {
 	if err := ValidateSource(req.Url); err != nil {
-		return nil, fmt.Errorf("invalid source: %w", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid source: %v", err)
 	}
+	return c.collect(ctx, req.JobId, collector.SourceConfig{
+		Type:          "git",
+		URL:           req.Url,
+		Branch:        req.Branch,
+		Token:         req.Token,
+		Commit:        req.Commit,
+		Tag:           req.Tag,
+		Subpath:       req.Subpath,
+		Depth:         int(req.Depth),
+		Submodules:    req.Submodules,
+		LFS:           req.Lfs,
+		SSHKey:        req.SshPrivateKey,
+		SSHKnownHosts: req.SshKnownHosts,
+		SSHKeyRef:     req.SshKeyRef,
+		BaseRef:       req.BaseRef,
+		HistoryDepth:  int(req.HistoryDepth),
+	}, collector.CollectFromGit)
+}
 
-	dir := filepath.Join(os.TempDir(), fmt.Sprintf("repo-%d", time.Now().UnixNano()))
-	cloneCmd := []string{"git", "clone"}
+// CollectFromPackage downloads a package version from its registry
+func (c *CollectorServer) CollectFromPackage(ctx context.Context, req *collectorpb.PackageRequest) (*collectorpb.CollectorResponse, error) {
+	cfg := collector.SourceConfig{
+		Type:      "package",
+		Ecosystem: req.Ecosystem,
+		Package:   req.Name,
+		Version:   req.Version,
+	}
+	if err := collector.ValidateSource(cfg); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return c.collect(ctx, req.JobId, cfg, collector.CollectFromPackage)
+}
 
-	if req.Branch != "" {
-		cloneCmd = append(cloneCmd, "-b", req.Branch)
+// CollectFromImage downloads a container image tarball and flattens its
+// layers
+func (c *CollectorServer) CollectFromImage(ctx context.Context, req *collectorpb.ImageRequest) (*collectorpb.CollectorResponse, error) {
+	if err := ValidateSource(req.Url); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid source: %v", err)
 	}
-	if req.Token != "" {
-		urlParts := strings.SplitN(req.Url, "://", 2)
-		if len(urlParts) == 2 {
-			req.Url = fmt.Sprintf("https://%s@%s", req.Token, urlParts[1])
+	return c.collect(ctx, req.JobId, collector.SourceConfig{Type: "image", URL: req.Url, SHA256: req.Sha256}, collector.CollectFromImage)
+}
+
+// CollectFromArchive downloads and extracts a ZIP/TAR archive
+func (c *CollectorServer) CollectFromArchive(ctx context.Context, req *collectorpb.ArchiveRequest) (*collectorpb.CollectorResponse, error) {
+	if err := ValidateSource(req.Url); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid source: %v", err)
+	}
+	return c.collect(ctx, req.JobId, collector.SourceConfig{
+		Type:        "archive",
+		URL:         req.Url,
+		NestedDepth: int(req.NestedDepth),
+		SHA256:      req.Sha256,
+	}, collector.CollectFromArchive)
+}
+
+// CollectFromURL downloads raw files from direct URLs
+func (c *CollectorServer) CollectFromURL(ctx context.Context, req *collectorpb.URLRequest) (*collectorpb.CollectorResponse, error) {
+	if err := ValidateSource(req.Url); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid source: %v", err)
+	}
+	return c.collect(ctx, req.JobId, collector.SourceConfig{Type: "url", URL: req.Url, SHA256: req.Sha256}, collector.CollectFromURL)
+}
+
+// CollectFromLocal collects a directory on the collector's filesystem that
+// lies below one of the COLLECTOR_LOCAL_ROOTS
+func (c *CollectorServer) CollectFromLocal(ctx context.Context, req *collectorpb.LocalRequest) (*collectorpb.CollectorResponse, error) {
+	return c.collect(ctx, req.JobId, collector.SourceConfig{
+		Type:     "local",
+		URL:      req.Path,
+		Snapshot: req.Snapshot,
+	}, collector.CollectFromLocal)
+}
+
+// CollectStream collects like the unary RPCs, sending progress events while
+// it runs and the CollectorResponse last
+func (c *CollectorServer) CollectStream(req *collectorpb.CollectRequest, stream collectorpb.CollectorService_CollectStreamServer) error {
+	ctx := collector.WithProgress(stream.Context(), func(p collector.Progress) {
+		// A failed send means the client is gone; the context ends the collection
+		_ = stream.Send(&collectorpb.CollectEvent{Event: &collectorpb.CollectEvent_Progress{Progress: &collectorpb.CollectProgress{
+			Stage:          p.Stage,
+			Step:           p.Step,
+			Percent:        int32(p.Percent),
+			Done:           p.Done,
+			Total:          p.Total,
+			BytesPerSecond: p.BytesPerSecond,
+		}}})
+	})
+
+	var resp *collectorpb.CollectorResponse
+	var err error
+	switch src := req.Source.(type) {
+	case *collectorpb.CollectRequest_Git:
+		resp, err = c.CollectFromGit(ctx, src.Git)
+	case *collectorpb.CollectRequest_Archive:
+		resp, err = c.CollectFromArchive(ctx, src.Archive)
+	case *collectorpb.CollectRequest_Url:
+		resp, err = c.CollectFromURL(ctx, src.Url)
+	case *collectorpb.CollectRequest_Local:
+		resp, err = c.CollectFromLocal(ctx, src.Local)
+	case *collectorpb.CollectRequest_Package:
+		resp, err = c.CollectFromPackage(ctx, src.Package)
+	case *collectorpb.CollectRequest_Image:
+		resp, err = c.CollectFromImage(ctx, src.Image)
+	default:
+		return status.Error(codes.InvalidArgument, "collect request names no source")
+	}
+	if err != nil {
+		return err
+	}
+	return stream.Send(&collectorpb.CollectEvent{Event: &collectorpb.CollectEvent_Result{Result: resp}})
+}
+
+// UploadSource receives an archive in chunks, verifies its SHA-256 against
+// the metadata and extracts it like CollectFromArchive
+func (c *CollectorServer) UploadSource(stream collectorpb.CollectorService_UploadSourceServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "empty upload")
+	}
+	if err != nil {
+		return err
+	}
+
+	meta := first.GetMetadata()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "first message must carry upload metadata")
+	}
+	expected, err := hex.DecodeString(meta.Sha256)
+	if err != nil || len(expected) != sha256.Size {
+		return status.Error(codes.InvalidArgument, "metadata must include a hex SHA-256 of the archive")
+	}
+	if meta.Format != "" {
+		if _, err := collector.NormalizeFormat(meta.Format); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	// Buffered outside any workspace so the archive itself is not collected
+	tmp, err := os.CreateTemp("", "upload-*.archive")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	w := io.MultiWriter(tmp, hash)
+	var size int64
+	for chunk := first; ; {
+		size += int64(len(chunk.Data))
+		if size > c.maxUpload {
+			return status.Errorf(codes.ResourceExhausted, "upload exceeds %d bytes", c.maxUpload)
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return err
+		}
+
+		chunk, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.Metadata != nil {
+			return status.Error(codes.InvalidArgument, "metadata is only allowed on the first message")
 		}
 	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
 
-	cloneCmd = append(cloneCmd, req.Url, dir)
+	if actual := hash.Sum(nil); !bytes.Equal(actual, expected) {
+		return status.Errorf(codes.InvalidArgument, "sha256 mismatch: got %x", actual)
+	}
+	log.Printf("📥 Received upload of %d bytes (sha256 %s)", size, meta.Sha256)
 
-	cmd := exec.CommandContext(ctx, cloneCmd[0], cloneCmd[1:]...)
-	output, err := cmd.CombinedOutput()
+	resp, err := c.collect(stream.Context(), meta.JobId, collector.SourceConfig{
+		Type:        "upload",
+		URL:         "sha256:" + meta.Sha256,
+		NestedDepth: int(meta.NestedDepth),
+	}, func(ctx context.Context, cfg collector.SourceConfig) (*collector.CollectionResult, error) {
+		return collector.CollectFromArchiveFile(ctx, cfg, tmp.Name(), meta.Format)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("git clone failed: %v\n%s", err, string(output))
+		return err
 	}
+	return stream.SendAndClose(resp)
+}
 
-	log.Printf("✅ Cloned repository: %s (branch: %s)", req.Url, req.Branch)
-	return &collectorpb.CollectorResponse{
-		Message: fmt.Sprintf("Repository cloned successfully at %s", dir),
-		Path:    dir,
-	}, nil
+// invalidArgument reports whether a collection failed because of the
+// request rather than the source
+func invalidArgument(err error) bool {
+	for _, target := range []error{
+		collector.ErrInvalidSource,
+		collector.ErrInvalidSourceType,
+		collector.ErrHostNotAllowed,
+		collector.ErrPrivateAddress,
+		collector.ErrLocalPathNotAllowed,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
 }
 
-// CollectFromArchive downloads and extracts a ZIP/TAR archive
-func (c *CollectorServer) CollectFromArchive(ctx context.Context, req *collectorpb.ArchiveRequest) (*collectorpb.CollectorResponse, error) {
-	if err := ValidateSource(req.Url); err != nil {
+// collect runs a collector into a fresh workspace referenced by jobID. A
+// failed collection's workspace is removed at once; unfinished ones are
+// removed on shutdown. Local sources read in place report their own
+// directory as the root, and releasing the workspace never touches it.
+func (c *CollectorServer) collect(ctx context.Context, jobID string, cfg collector.SourceConfig, fn func(context.Context, collector.SourceConfig) (*collector.CollectionResult, error)) (_ *collectorpb.CollectorResponse, err error) {
+	ws, err := c.workspaces.Create(cfg.Type, jobID)
+	if errors.Is(err, collector.ErrWorkspaceQuota) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			c.workspaces.Remove(ws.ID)
+		}
+	}()
+
+	cfg.LocalPath = ws.Path
+	log.Printf("📦 Collecting %s source %s into workspace %s", cfg.Type, utils.RedactURL(cfg.URL), ws.ID)
+	result, err := fn(ctx, cfg)
+	if invalidArgument(err) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s collection failed: %v", cfg.Type, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s collection failed: %w", cfg.Type, err)
+	}
+	root := result.Root
+	var digest string
+	if c.artifacts != nil {
+		if digest, err = artifact.PackDir(ctx, c.artifacts, root); err != nil {
+			return nil, fmt.Errorf("failed to store workspace snapshot: %w", err)
+		}
+		log.Printf("📤 Stored snapshot of workspace %s as %s", ws.ID, digest)
+	}
+
+	resp := &collectorpb.CollectorResponse{
+		Message:        fmt.Sprintf("Collected %d files (%d bytes)", len(result.Files), result.TotalSize),
+		Path:           root,
+		RepoConfig:     readRepoConfig(root),
+		ResolvedCommit: result.Commit,
+		LfsPointers:    lfsPointers(result),
+		WorkspaceId:    ws.ID,
+		ArtifactDigest: digest,
+		Languages:      result.Language,
+		Manifest:       manifest(result),
+		Provenance:     provenance(result.Provenance),
+		MerkleRoot:     result.MerkleRoot,
+		Changes:        changes(result.Changes),
+		History:        history(result.History),
+		Image:          image(result.Image),
+	}
+	meta, err := proto.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	err = c.workspaces.Commit(ws.ID, root, meta)
+	if errors.Is(err, collector.ErrWorkspaceQuota) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	targetDir := filepath.Join(os.TempDir(), fmt.Sprintf("archive-%d", time.Now().UnixNano()))
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
+	log.Printf("✅ Collected %d files (%d bytes) into %s, languages %v, merkle root %s", len(result.Files), result.TotalSize, root, result.Language, result.MerkleRoot)
+	return resp, nil
+}
+
+// AcquireWorkspace adds a job's reference to a finished workspace, so the
+// job can analyze it without collecting again
+func (c *CollectorServer) AcquireWorkspace(ctx context.Context, req *collectorpb.AcquireWorkspaceRequest) (*collectorpb.CollectorResponse, error) {
+	if req.JobId == "" {
+		return nil, status.Error(codes.InvalidArgument, "job_id is required")
+	}
+	ws, err := c.workspaces.Acquire(req.WorkspaceId, req.JobId)
+	if errors.Is(err, collector.ErrWorkspaceNotFound) {
+		return nil, status.Errorf(codes.NotFound, "workspace %s not found", req.WorkspaceId)
+	}
+	if errors.Is(err, collector.ErrWorkspaceNotReady) {
+		return nil, status.Errorf(codes.FailedPrecondition, "workspace %s: %v", req.WorkspaceId, err)
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	// Use system commands for simplicity (curl + tar or unzip)
-	log.Printf("📦 Downloading archive from %s", req.Url)
-	var cmd *exec.Cmd
-	if strings.HasSuffix(req.Url, ".zip") {
-		cmd = exec.CommandContext(ctx, "bash", "-c", fmt.Sprintf("curl -L %s -o /tmp/archive.zip && unzip /tmp/archive.zip -d %s", req.Url, targetDir))
+	resp := &collectorpb.CollectorResponse{}
+	if len(ws.Meta) > 0 {
+		if err := proto.Unmarshal(ws.Meta, resp); err != nil {
+			return nil, fmt.Errorf("failed to read workspace %s meta: %w", ws.ID, err)
+		}
 	} else {
-		cmd = exec.CommandContext(ctx, "bash", "-c", fmt.Sprintf("curl -L %s | tar -xz -C %s", req.Url, targetDir))
+		// Adopted from a process that kept no meta; only the files are known
+		resp = &collectorpb.CollectorResponse{Path: ws.Root, RepoConfig: readRepoConfig(ws.Root), WorkspaceId: ws.ID}
 	}
+	resp.Message = fmt.Sprintf("Reusing workspace %s", ws.ID)
+	log.Printf("♻️ Job %s reuses workspace %s", req.JobId, ws.ID)
+	return resp, nil
+}
 
-	output, err := cmd.CombinedOutput()
+// ReleaseWorkspace drops a job's reference to a workspace
+func (c *CollectorServer) ReleaseWorkspace(ctx context.Context, req *collectorpb.ReleaseWorkspaceRequest) (*collectorpb.ReleaseWorkspaceResponse, error) {
+	if req.JobId == "" {
+		return nil, status.Error(codes.InvalidArgument, "job_id is required")
+	}
+	remaining, err := c.workspaces.Release(req.WorkspaceId, req.JobId)
+	if errors.Is(err, collector.ErrWorkspaceNotFound) {
+		return nil, status.Errorf(codes.NotFound, "workspace %s not found", req.WorkspaceId)
+	}
+	if errors.Is(err, collector.ErrWorkspaceNotReady) {
+		return nil, status.Errorf(codes.FailedPrecondition, "workspace %s: %v", req.WorkspaceId, err)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("archive extraction failed: %v\n%s", err, string(output))
+		return nil, err
+	}
+	if remaining == 0 {
+		log.Printf("🧹 Released workspace %s", req.WorkspaceId)
 	}
+	return &collectorpb.ReleaseWorkspaceResponse{Removed: remaining == 0, RemainingRefs: int32(remaining)}, nil
+}
 
-	log.Printf("✅ Archive extracted to %s", targetDir)
-	return &collectorpb.CollectorResponse{
-		Message: "Archive downloaded and extracted successfully",
-		Path:    targetDir,
-	}, nil
+// ListWorkspaces reports workspaces and disk usage
+func (c *CollectorServer) ListWorkspaces(ctx context.Context, req *collectorpb.ListWorkspacesRequest) (*collectorpb.WorkspaceList, error) {
+	used, quota := c.workspaces.Usage()
+	resp := &collectorpb.WorkspaceList{UsedBytes: used, QuotaBytes: quota}
+	for _, ws := range c.workspaces.List(req.JobId) {
+		info := &collectorpb.WorkspaceInfo{
+			Id:           ws.ID,
+			Path:         ws.Path,
+			Root:         ws.Root,
+			SourceType:   ws.SourceType,
+			Jobs:         ws.Jobs,
+			SizeBytes:    ws.Size,
+			Ready:        ws.Ready,
+			CreatedUnix:  ws.CreatedAt.Unix(),
+			LastUsedUnix: ws.LastUsed.Unix(),
+		}
+		if expires := c.workspaces.ExpiresAt(ws); !expires.IsZero() {
+			info.ExpiresUnix = expires.Unix()
+		}
+		resp.Workspaces = append(resp.Workspaces, info)
+	}
+	return resp, nil
 }
 
-// CollectFromURL downloads raw files from direct URLs
-func (c *CollectorServer) CollectFromURL(ctx context.Context, req *collectorpb.URLRequest) (*collectorpb.CollectorResponse, error) {
-	if err := ValidateSource(req.Url); err != nil {
-		return nil, err
+// ValidateSource checks a source without collecting it: URL safety, git
+// credentials and branch resolution, and an estimate of the download size
+func (c *CollectorServer) ValidateSource(ctx context.Context, req *collectorpb.ValidateRequest) (*collectorpb.ValidateResponse, error) {
+	cfg := collector.SourceConfig{
+		Type:          req.Type,
+		URL:           req.Url,
+		Branch:        req.Branch,
+		Token:         req.Token,
+		Commit:        req.Commit,
+		Tag:           req.Tag,
+		SSHKey:        req.SshPrivateKey,
+		SSHKnownHosts: req.SshKnownHosts,
+		SSHKeyRef:     req.SshKeyRef,
+		Ecosystem:     req.PackageEcosystem,
+		Package:       req.PackageName,
+		Version:       req.PackageVersion,
+	}
+	if cfg.Type == "" {
+		cfg.Type = "git"
+	}
+
+	if cfg.Type == "local" {
+		if _, err := collector.CheckLocalPath(cfg.URL); err != nil {
+			return &collectorpb.ValidateResponse{Valid: false, Message: err.Error()}, nil
+		}
+	} else if cfg.Type != "package" {
+		if err := ValidateSource(cfg.URL); err != nil {
+			return &collectorpb.ValidateResponse{Valid: false, Message: err.Error()}, nil
+		}
+	}
+	if err := collector.ValidateSource(cfg); err != nil {
+		return &collectorpb.ValidateResponse{Valid: false, Message: err.Error()}, nil
 	}
 
-	target := filepath.Join(os.TempDir(), fmt.Sprintf("file-%d", time.Now().UnixNano()))
-	cmd := exec.CommandContext(ctx, "curl", "-L", req.Url, "-o", target)
+	resp := &collectorpb.ValidateResponse{Valid: true, Message: "Source is valid"}
+	if cfg.Type == "git" {
+		commit, err := collector.ResolveRef(ctx, cfg)
+		if err != nil {
+			return &collectorpb.ValidateResponse{Valid: false, Message: err.Error()}, nil
+		}
+		resp.ResolvedCommit = commit
+	}
 
-	output, err := cmd.CombinedOutput()
+	size, err := collector.EstimateSize(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("download failed: %v\n%s", err, string(output))
+		log.Printf("⚠️ Could not estimate size of %s: %v", utils.RedactURL(cfg.URL), err)
 	}
+	resp.EstimatedSizeBytes = size
 
-	log.Printf("✅ File downloaded to %s", target)
-	return &collectorpb.CollectorResponse{
-		Message: "File downloaded successfully",
-		Path:    target,
-	}, nil
+	log.Printf("✅ Validated source %s (commit: %s, ~%d bytes)", utils.RedactURL(cfg.URL), resp.ResolvedCommit, size)
+	return resp, nil
+}
+
+// manifest converts a collection's file manifest
+func manifest(result *collector.CollectionResult) []*collectorpb.ManifestEntry {
+	entries := collector.Manifest(result)
+	out := make([]*collectorpb.ManifestEntry, len(entries))
+	for i, e := range entries {
+		out[i] = &collectorpb.ManifestEntry{
+			Path:     e.Path,
+			Size:     e.Size,
+			Sha256:   e.SHA256,
+			Class:    e.Class,
+			Language: e.Language,
+		}
+	}
+	return out
+}
+
+// provenance converts a collection's provenance; times are RFC 3339
+func provenance(p collector.Provenance) *collectorpb.Provenance {
+	out := &collectorpb.Provenance{
+		SourceType: p.SourceType,
+		Url:        p.URL,
+		Commit:     p.Commit,
+		Author:     p.Author,
+		FetchedAt:  p.FetchedAt.Format(time.RFC3339),
+	}
+	if !p.CommitTime.IsZero() {
+		out.CommitTime = p.CommitTime.Format(time.RFC3339)
+	}
+	return out
+}
+
+// changes converts the change set of a pull request collection
+func changes(cs *changeset.ChangeSet) *collectorpb.ChangeSet {
+	if cs == nil {
+		return nil
+	}
+	out := &collectorpb.ChangeSet{
+		BaseRef:    cs.BaseRef,
+		BaseCommit: cs.BaseCommit,
+		HeadCommit: cs.HeadCommit,
+		MergeBase:  cs.MergeBase,
+	}
+	for _, f := range cs.Files {
+		file := &collectorpb.ChangedFile{Path: f.Path, OldPath: f.OldPath, Status: f.Status, Binary: f.Binary}
+		for _, h := range f.Hunks {
+			file.Hunks = append(file.Hunks, &collectorpb.Hunk{
+				OldStart: int32(h.OldStart),
+				OldLines: int32(h.OldLines),
+				NewStart: int32(h.NewStart),
+				NewLines: int32(h.NewLines),
+			})
+		}
+		out.Files = append(out.Files, file)
+	}
+	log.Printf("🔀 %d files changed since merge base %s with %s", len(out.Files), cs.MergeBase, cs.BaseRef)
+	return out
+}
+
+// history converts the mined history of a git collection
+func history(h *collector.History) *collectorpb.History {
+	if h == nil {
+		return nil
+	}
+	out := &collectorpb.History{Commits: int32(h.Commits)}
+	for _, f := range h.Files {
+		out.Files = append(out.Files, &collectorpb.FileHistory{
+			Path:         f.Path,
+			Commits:      int32(f.Commits),
+			LinesAdded:   int64(f.LinesAdded),
+			LinesRemoved: int64(f.LinesRemoved),
+			Authors:      int32(f.Authors),
+			LastModified: f.LastModified.Format(time.RFC3339),
+			LastCommit:   f.LastCommit,
+		})
+	}
+	log.Printf("📜 Mined %d commits touching %d files", h.Commits, len(h.Files))
+	return out
+}
+
+// image converts the configuration and package inventory of an image
+func image(info *collector.ImageInfo) *collectorpb.ImageInfo {
+	if info == nil {
+		return nil
+	}
+	out := &collectorpb.ImageInfo{
+		RepoTags:     info.RepoTags,
+		Os:           info.OS,
+		Architecture: info.Architecture,
+		Entrypoint:   info.Entrypoint,
+		Cmd:          info.Cmd,
+		Env:          info.Env,
+		ExposedPorts: info.ExposedPorts,
+		User:         info.User,
+		WorkingDir:   info.WorkingDir,
+		Labels:       info.Labels,
+		Layers:       int32(info.Layers),
+		Warnings:     info.Warnings,
+	}
+	for _, p := range info.Packages {
+		out.Packages = append(out.Packages, &collectorpb.OSPackage{
+			Manager:      p.Manager,
+			Name:         p.Name,
+			Version:      p.Version,
+			Architecture: p.Architecture,
+			Source:       p.Source,
+		})
+	}
+	log.Printf("🐳 Flattened %d image layers (%s/%s), %d OS packages", info.Layers, info.OS, info.Architecture, len(info.Packages))
+	for _, w := range info.Warnings {
+		log.Printf("⚠️ %s", w)
+	}
+	return out
+}
+
+// lfsPointers lists the unfetched LFS pointer files of a collection
+func lfsPointers(result *collector.CollectionResult) []string {
+	var paths []string
+	for _, f := range result.Files {
+		if !f.LFSPointer {
+			continue
+		}
+		if rel, err := filepath.Rel(result.Root, f.Path); err == nil {
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+	}
+	if len(paths) > 0 {
+		log.Printf("⚠️ %d Git LFS pointer files were not fetched", len(paths))
+	}
+	return paths
+}
+
+// readRepoConfig returns the workspace's .unarya.yml, if any. Validation is
+// left to the orchestrator so problems end up in the job result.
+func readRepoConfig(dir string) string {
+	data, err := repoconfig.Read(dir)
+	if err != nil {
+		log.Printf("⚠️ Failed to read %s in %s: %v", repoconfig.FileName, dir, err)
+		return ""
+	}
+	if data != nil {
+		log.Printf("📄 Found %s in %s", repoconfig.FileName, dir)
+	}
+	return string(data)
 }
 
 // ValidateSource performs security checks to prevent unsafe URLs or paths
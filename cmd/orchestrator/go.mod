@@ -1,4 +1,4 @@
-module orchestrator
+module github.com/unarya/unarya/cmd/orchestrator
 
 go 1.25.0
 
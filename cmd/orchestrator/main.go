@@ -2,17 +2,35 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/unarya/unarya/internal/collector"
+	"github.com/unarya/unarya/internal/orchestrator"
+	"github.com/unarya/unarya/internal/shared/changeset"
+	"github.com/unarya/unarya/internal/shared/config"
+	sharedgrpc "github.com/unarya/unarya/internal/shared/grpc"
+	"github.com/unarya/unarya/internal/shared/repoconfig"
+	"github.com/unarya/unarya/internal/shared/utils"
 	"github.com/unarya/unarya/lib/proto/pb/aipb"
 	"github.com/unarya/unarya/lib/proto/pb/collectorpb"
 	"github.com/unarya/unarya/lib/proto/pb/orchestratorpb"
 	"github.com/unarya/unarya/lib/proto/pb/parserpb"
 	"github.com/unarya/unarya/lib/proto/pb/security_scanpb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ===============================================
@@ -20,6 +38,14 @@ import (
 // collector → parser → ai → security_scan
 // ===============================================
 
+const reachabilityTimeout = 3 * time.Second
+
+// releaseTimeout bounds the ReleaseWorkspace call made after a pipeline
+const releaseTimeout = 10 * time.Second
+
+// jobIDPattern restricts the job IDs callers may choose
+var jobIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
 type OrchestratorServer struct {
 	orchestratorpb.UnimplementedOrchestratorServiceServer
 
@@ -27,58 +53,532 @@ type OrchestratorServer struct {
 	parserClient    parserpb.ParserServiceClient
 	aiClient        aipb.AIServiceClient
 	securityClient  security_scanpb.SecurityScanServiceClient
+	stageConns      map[string]*grpc.ClientConn
+
+	jobs   *orchestrator.JobStore
+	intake *sharedgrpc.Intake
+	slots  chan struct{}
 }
 
-// StartPipeline — coordinates the full pipeline flow
+// StartPipeline — records a job and runs the full pipeline for it
 func (s *OrchestratorServer) StartPipeline(ctx context.Context, req *orchestratorpb.PipelineRequest) (*orchestratorpb.PipelineResponse, error) {
-	log.Printf("[Orchestrator] Received pipeline request for repo: %s", req.RepositoryUrl)
+	log.Printf("[Orchestrator] Received pipeline request for repo: %s", utils.RedactURL(req.RepositoryUrl))
+
+	if _, err := orchestrator.ResolveTemplate(req.Template); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	id := req.JobId
+	if id == "" {
+		id = utils.NewID("job")
+	} else if !jobIDPattern.MatchString(id) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid job id %q", id)
+	}
+	job := orchestrator.Job{
+		ID:        id,
+		Request:   requestFromProto(req),
+		Status:    orchestrator.JobQueued,
+		CreatedAt: time.Now(),
+	}
+	if !s.jobs.Create(job) {
+		return nil, status.Errorf(codes.AlreadyExists, "job %s already exists", id)
+	}
+
+	resp, err := s.runJob(ctx, job)
+	if resp != nil {
+		resp.JobId = job.ID
+	}
+	return resp, err
+}
+
+// runJob waits for a free pipeline slot, then executes the job and records its outcome
+func (s *OrchestratorServer) runJob(ctx context.Context, job orchestrator.Job) (*orchestratorpb.PipelineResponse, error) {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		// Left queued when shutting down so the checkpoint picks it up
+		if !s.intake.Draining() {
+			s.jobs.SetStatus(job.ID, orchestrator.JobCancelled, ctx.Err().Error())
+		}
+		return nil, status.Errorf(codes.Unavailable, "job %s not started: %v", job.ID, ctx.Err())
+	}
+	defer func() { <-s.slots }()
+
+	s.jobs.SetStatus(job.ID, orchestrator.JobRunning, "")
+	resp, err := s.runPipeline(ctx, job.ID, job.Request)
+	switch {
+	case err != nil && ctx.Err() != nil && s.intake.Draining():
+		s.jobs.SetStatus(job.ID, orchestrator.JobInterrupted, resp.Details)
+	case err != nil, resp.Status == "failed":
+		s.jobs.SetStatus(job.ID, orchestrator.JobFailed, resp.Details)
+	default:
+		s.jobs.SetStatus(job.ID, orchestrator.JobSuccess, resp.Details)
+	}
+	return resp, err
+}
+
+// runPipeline — coordinates the pipeline flow for the request's template
+func (s *OrchestratorServer) runPipeline(ctx context.Context, jobID string, req orchestrator.Request) (*orchestratorpb.PipelineResponse, error) {
+	tmpl, err := orchestrator.ResolveTemplate(req.Template)
+	if err != nil {
+		return s.fail("template", err)
+	}
+	var details []string
 
 	// === 1️⃣ Collector stage ===
-	collected, err := s.collectorClient.CollectFromGit(ctx, &collectorpb.GitRequest{
-		Url: req.RepositoryUrl,
-	})
+	s.jobs.SetStage(jobID, orchestrator.StageCollector)
+	collected, err := s.collect(ctx, jobID, req)
 	if err != nil {
-		return s.fail("collector", err)
+		return s.fail(orchestrator.StageCollector, err)
 	}
-	log.Println("[Orchestrator] ✓ Repository collected")
+	defer s.releaseWorkspace(collected.WorkspaceId, jobID)
+	log.Printf("[Orchestrator] ✓ Repository collected: %d files, merkle root %s", len(collected.Manifest), collected.MerkleRoot)
 
-	// === 2️⃣ Parser stage ===
-	parsed, err := s.parserClient.ParseCode(ctx, &parserpb.ParseRequest{
-		SourcePath: collected.Path,
-	})
+	repoCfg, err := s.loadRepoConfig(collected)
 	if err != nil {
-		return s.fail("parser", err)
+		return s.failConfig(err)
+	}
+	// Unfetched LFS pointers are not the files they stand for
+	repoCfg.IgnorePaths(collected.LfsPointers...)
+	encodedCfg := repoCfg.Encode()
+	changes := changeSetFromProto(collected.Changes)
+	if changes != nil {
+		details = append(details, fmt.Sprintf("Changed files: %d since %s (merge base %s)", len(changes.Files), changes.BaseRef, changes.MergeBase))
+	}
+	encodedChanges := changes.Encode()
+	if h := collected.History; h != nil {
+		details = append(details, fmt.Sprintf("History: %d commits mined; most changed: %s", h.Commits, strings.Join(hotspots(h, 5), ", ")))
+	}
+	if img := collected.Image; img != nil {
+		user := img.User
+		if user == "" {
+			user = "root"
+		}
+		details = append(details, fmt.Sprintf("Image: %d layers, %s/%s, user %s, ports %v, %d OS packages",
+			img.Layers, img.Os, img.Architecture, user, img.ExposedPorts, len(img.Packages)))
+	}
+
+	// === 2️⃣ Parser stage ===
+	var parsed *parserpb.ParseResponse
+	if tmpl.Has(orchestrator.StageParser) {
+		s.jobs.SetStage(jobID, orchestrator.StageParser)
+		parsed, err = s.parserClient.ParseCode(ctx, &parserpb.ParseRequest{
+			SourcePath:     collected.Path,
+			RepoConfig:     encodedCfg,
+			ArtifactDigest: collected.ArtifactDigest,
+			ChangeSet:      encodedChanges,
+		})
+		if err != nil {
+			return s.fail(orchestrator.StageParser, err)
+		}
+		log.Println("[Orchestrator] ✓ Parsing completed")
 	}
-	log.Println("[Orchestrator] ✓ Parsing completed")
 
 	// === 3️⃣ AI analysis stage ===
-	analyzed, err := s.aiClient.AnalyzeCode(ctx, &aipb.AIAnalyzeRequest{
-		Language:      parsed.Language,
-		CodeStructure: parsed.CodeStructure,
-	})
-	if err != nil {
-		return s.fail("ai", err)
+	if tmpl.Has(orchestrator.StageAI) {
+		s.jobs.SetStage(jobID, orchestrator.StageAI)
+		analyzed, err := s.aiClient.AnalyzeCode(ctx, &aipb.AIAnalyzeRequest{
+			Language:      parsed.GetLanguage(),
+			CodeStructure: parsed.GetCodeStructure(),
+			RepoConfig:    encodedCfg,
+		})
+		if err != nil {
+			return s.fail(orchestrator.StageAI, err)
+		}
+		log.Println("[Orchestrator] ✓ AI analysis completed")
+		details = append(details, fmt.Sprintf("AI insights: %s (confidence: %s)", analyzed.Insights, analyzed.Confidence))
 	}
-	log.Println("[Orchestrator] ✓ AI analysis completed")
 
 	// === 4️⃣ Security scanning stage ===
-	scanned, err := s.securityClient.ScanForVulnerabilities(ctx, &security_scanpb.ScanRequest{
-		SourcePath: collected.Path,
-	})
-	if err != nil {
-		return s.fail("security_scan", err)
+	if tmpl.Has(orchestrator.StageSecurityScan) {
+		s.jobs.SetStage(jobID, orchestrator.StageSecurityScan)
+		scanned, err := s.securityClient.ScanForVulnerabilities(ctx, &security_scanpb.ScanRequest{
+			SourcePath:     collected.Path,
+			RepoConfig:     encodedCfg,
+			ArtifactDigest: collected.ArtifactDigest,
+			ChangeSet:      encodedChanges,
+		})
+		if err != nil {
+			return s.fail(orchestrator.StageSecurityScan, err)
+		}
+		log.Println("[Orchestrator] ✓ Security scan completed")
+		details = append(details,
+			fmt.Sprintf("Security findings: %d issues", scanned.TotalFinds),
+			fmt.Sprintf("Report summary: %s", scanned.Report),
+		)
 	}
-	log.Println("[Orchestrator] ✓ Security scan completed")
 
 	// === 5️⃣ Aggregate results ===
-	details := fmt.Sprintf(
-		"AI insights: %s (confidence: %s)\nSecurity findings: %d issues\nReport summary: %s",
-		analyzed.Insights, analyzed.Confidence, scanned.TotalFinds, scanned.Report,
-	)
+	return &orchestratorpb.PipelineResponse{
+		Status:         "success",
+		Details:        strings.Join(details, "\n"),
+		ResolvedCommit: collected.ResolvedCommit,
+		MerkleRoot:     collected.MerkleRoot,
+	}, nil
+}
+
+// collect streams the collection of the request's source, relaying the
+// collector's progress to the job's watchers. A request naming a workspace
+// reuses its collection instead; either way the job holds a reference that
+// runPipeline releases.
+func (s *OrchestratorServer) collect(ctx context.Context, jobID string, req orchestrator.Request) (*collectorpb.CollectorResponse, error) {
+	if req.WorkspaceID != "" {
+		return s.collectorClient.AcquireWorkspace(ctx, &collectorpb.AcquireWorkspaceRequest{WorkspaceId: req.WorkspaceID, JobId: jobID})
+	}
+	stream, err := s.collectorClient.CollectStream(ctx, collectRequest(jobID, req))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil, errors.New("collector stream ended without a result")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if result := event.GetResult(); result != nil {
+			return result, nil
+		}
+		if p := event.GetProgress(); p != nil {
+			s.jobs.ReportProgress(jobID, orchestrator.JobProgress{
+				Stage:          p.Stage,
+				Step:           p.Step,
+				Percent:        int(p.Percent),
+				Done:           p.Done,
+				Total:          p.Total,
+				BytesPerSecond: p.BytesPerSecond,
+			})
+		}
+	}
+}
+
+// collectRequest builds the collector request matching the source type
+func collectRequest(jobID string, req orchestrator.Request) *collectorpb.CollectRequest {
+	switch req.SourceType {
+	case "archive":
+		return &collectorpb.CollectRequest{Source: &collectorpb.CollectRequest_Archive{Archive: &collectorpb.ArchiveRequest{
+			Url:         req.RepositoryURL,
+			NestedDepth: int32(req.NestedDepth),
+			Sha256:      req.SHA256,
+			JobId:       jobID,
+		}}}
+	case "url":
+		return &collectorpb.CollectRequest{Source: &collectorpb.CollectRequest_Url{Url: &collectorpb.URLRequest{Url: req.RepositoryURL, Sha256: req.SHA256, JobId: jobID}}}
+	case "image":
+		return &collectorpb.CollectRequest{Source: &collectorpb.CollectRequest_Image{Image: &collectorpb.ImageRequest{Url: req.RepositoryURL, Sha256: req.SHA256, JobId: jobID}}}
+	case "package":
+		return &collectorpb.CollectRequest{Source: &collectorpb.CollectRequest_Package{Package: &collectorpb.PackageRequest{
+			Ecosystem: req.PackageEcosystem,
+			Name:      req.PackageName,
+			Version:   req.PackageVersion,
+			JobId:     jobID,
+		}}}
+	case "local":
+		return &collectorpb.CollectRequest{Source: &collectorpb.CollectRequest_Local{Local: &collectorpb.LocalRequest{
+			Path:     req.RepositoryURL,
+			Snapshot: req.Snapshot,
+			JobId:    jobID,
+		}}}
+	default:
+		return &collectorpb.CollectRequest{Source: &collectorpb.CollectRequest_Git{Git: &collectorpb.GitRequest{
+			Url:           req.RepositoryURL,
+			Branch:        req.Branch,
+			Token:         req.Token,
+			Commit:        req.Commit,
+			Tag:           req.Tag,
+			Subpath:       req.Subpath,
+			Depth:         int32(req.Depth),
+			Submodules:    req.Submodules,
+			Lfs:           req.LFS,
+			SshPrivateKey: req.SSHKey,
+			SshKnownHosts: req.SSHKnownHosts,
+			SshKeyRef:     req.SSHKeyRef,
+			JobId:         jobID,
+			BaseRef:       req.BaseRef,
+			HistoryDepth:  int32(req.HistoryDepth),
+		}}}
+	}
+}
+
+// WatchJob streams a job's events until it finishes. The first event is
+// the job's current state.
+func (s *OrchestratorServer) WatchJob(req *orchestratorpb.WatchJobRequest, stream orchestratorpb.OrchestratorService_WatchJobServer) error {
+	job, events, stop, ok := s.jobs.Watch(req.JobId)
+	if !ok {
+		return status.Errorf(codes.NotFound, "job %s not found", req.JobId)
+	}
+	defer stop()
+
+	last := jobEvent(orchestrator.JobEvent{JobID: job.ID, Status: job.Status, Stage: job.Stage, Details: job.Details, Time: job.UpdatedAt})
+	if !orchestrator.JobFinished(job.Status) {
+		last.Details = ""
+	}
+	if err := stream.Send(last); err != nil {
+		return err
+	}
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				if orchestrator.JobFinished(last.Status) {
+					return nil
+				}
+				// Watches end early at shutdown; report a job that
+				// finished meanwhile
+				if job, ok := s.jobs.Get(req.JobId); ok && orchestrator.JobFinished(job.Status) {
+					return stream.Send(jobEvent(orchestrator.JobEvent{JobID: job.ID, Status: job.Status, Details: job.Details, Time: job.UpdatedAt}))
+				}
+				return status.Errorf(codes.Unavailable, "job %s is no longer watched", req.JobId)
+			}
+			last = jobEvent(event)
+			if err := stream.Send(last); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func jobEvent(e orchestrator.JobEvent) *orchestratorpb.JobEvent {
+	out := &orchestratorpb.JobEvent{
+		JobId:   e.JobID,
+		Status:  e.Status,
+		Stage:   e.Stage,
+		Details: e.Details,
+		Time:    e.Time.Format(time.RFC3339),
+	}
+	if p := e.Progress; p != nil {
+		out.Progress = &orchestratorpb.JobProgress{
+			Stage:          p.Stage,
+			Step:           p.Step,
+			Percent:        int32(p.Percent),
+			Done:           p.Done,
+			Total:          p.Total,
+			BytesPerSecond: p.BytesPerSecond,
+		}
+	}
+	return out
+}
+
+// releaseWorkspace tells the collector a job no longer needs its workspace.
+// It runs after the pipeline, so it does not use the job's context, which
+// may already be cancelled.
+func (s *OrchestratorServer) releaseWorkspace(workspaceID, jobID string) {
+	if workspaceID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if _, err := s.collectorClient.ReleaseWorkspace(ctx, &collectorpb.ReleaseWorkspaceRequest{WorkspaceId: workspaceID, JobId: jobID}); err != nil {
+		log.Printf("[Orchestrator] ⚠️ Failed to release workspace %s: %v", workspaceID, err)
+	}
+}
+
+// ValidatePipeline — dry run: checks the source, credentials, stage services
+// and template of a request without collecting anything
+func (s *OrchestratorServer) ValidatePipeline(ctx context.Context, req *orchestratorpb.PipelineRequest) (*orchestratorpb.PipelinePlan, error) {
+	log.Printf("[Orchestrator] Validating pipeline request for repo: %s", utils.RedactURL(req.RepositoryUrl))
+	r := requestFromProto(req)
+	plan := &orchestratorpb.PipelinePlan{}
+
+	tmpl, err := orchestrator.ResolveTemplate(r.Template)
+	if err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+		return plan, nil
+	}
+	plan.Template = tmpl.Name
+
+	// A reused workspace has no source left to check
+	sourceOK := r.WorkspaceID == ""
+	if sourceOK {
+		if err := collector.ValidateSource(collector.SourceConfig{
+			Type:      r.SourceType,
+			URL:       r.RepositoryURL,
+			Branch:    r.Branch,
+			Commit:    r.Commit,
+			Tag:       r.Tag,
+			Subpath:   r.Subpath,
+			Ecosystem: r.PackageEcosystem,
+			Package:   r.PackageName,
+			Version:   r.PackageVersion,
+		}); err != nil {
+			plan.Errors = append(plan.Errors, fmt.Sprintf("invalid source: %v", err))
+			sourceOK = false
+		}
+	}
+
+	plan.Stages = s.checkStages(ctx, tmpl)
+	collectorOK := false
+	for _, stage := range plan.Stages {
+		if !stage.Reachable {
+			plan.Errors = append(plan.Errors, fmt.Sprintf("stage %s unreachable: %s", stage.Name, stage.Error))
+		} else if stage.Name == orchestrator.StageCollector {
+			collectorOK = true
+		}
+	}
+
+	// Credentials, branch resolution and size estimate are checked by the collector
+	if sourceOK && collectorOK {
+		validated, err := s.collectorClient.ValidateSource(ctx, &collectorpb.ValidateRequest{
+			Url:              r.RepositoryURL,
+			Type:             r.SourceType,
+			Branch:           r.Branch,
+			Token:            r.Token,
+			Commit:           r.Commit,
+			Tag:              r.Tag,
+			SshPrivateKey:    r.SSHKey,
+			SshKnownHosts:    r.SSHKnownHosts,
+			SshKeyRef:        r.SSHKeyRef,
+			PackageEcosystem: r.PackageEcosystem,
+			PackageName:      r.PackageName,
+			PackageVersion:   r.PackageVersion,
+		})
+		switch {
+		case err != nil:
+			plan.Errors = append(plan.Errors, fmt.Sprintf("collector validation failed: %v", err))
+		case !validated.Valid:
+			plan.Errors = append(plan.Errors, validated.Message)
+		default:
+			plan.ResolvedCommit = validated.ResolvedCommit
+			plan.EstimatedSizeBytes = validated.EstimatedSizeBytes
+		}
+	}
+
+	plan.Valid = len(plan.Errors) == 0
+	log.Printf("[Orchestrator] Validation finished (valid: %t, %d error(s))", plan.Valid, len(plan.Errors))
+	return plan, nil
+}
+
+// checkStages probes every service the template needs, in parallel
+func (s *OrchestratorServer) checkStages(ctx context.Context, tmpl orchestrator.PipelineTemplate) []*orchestratorpb.PlannedStage {
+	stages := make([]*orchestratorpb.PlannedStage, len(tmpl.Stages))
+	var wg sync.WaitGroup
+	for i, name := range tmpl.Stages {
+		conn := s.stageConns[name]
+		stages[i] = &orchestratorpb.PlannedStage{Name: name, Target: conn.Target()}
+
+		wg.Add(1)
+		go func(stage *orchestratorpb.PlannedStage) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, reachabilityTimeout)
+			defer cancel()
+			if err := sharedgrpc.CheckReachable(probeCtx, conn); err != nil {
+				stage.Error = err.Error()
+				return
+			}
+			stage.Reachable = true
+		}(stages[i])
+	}
+	wg.Wait()
+	return stages
+}
+
+func requestFromProto(req *orchestratorpb.PipelineRequest) orchestrator.Request {
+	sourceType := req.SourceType
+	if sourceType == "" {
+		sourceType = "git"
+	}
+	return orchestrator.Request{
+		RepositoryURL: req.RepositoryUrl,
+		Branch:        req.Branch,
+		Token:         req.Token,
+		SourceType:    sourceType,
+		Template:      req.Template,
+		NestedDepth:   int(req.NestedDepth),
+		Snapshot:      req.Snapshot,
+		Commit:        req.Commit,
+		Tag:           req.Tag,
+		Subpath:       req.Subpath,
+		Depth:         int(req.Depth),
+		Submodules:    req.Submodules,
+		LFS:           req.Lfs,
+		SSHKey:        req.SshPrivateKey,
+		Credentials:   req.Token != "" || req.SshPrivateKey != "" || utils.StripCredentials(req.RepositoryUrl) != req.RepositoryUrl,
+		SSHKnownHosts: req.SshKnownHosts,
+		SSHKeyRef:     req.SshKeyRef,
+		SHA256:        req.Sha256,
+		BaseRef:       req.BaseRef,
+		HistoryDepth:  int(req.HistoryDepth),
+
+		PackageEcosystem: req.PackageEcosystem,
+		PackageName:      req.PackageName,
+		PackageVersion:   req.PackageVersion,
+
+		WorkspaceID: req.WorkspaceId,
+	}
+}
 
+// hotspots names the n files with the most commits, then the most churn
+func hotspots(h *collectorpb.History, n int) []string {
+	files := slices.Clone(h.Files)
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Commits != files[j].Commits {
+			return files[i].Commits > files[j].Commits
+		}
+		return files[i].LinesAdded+files[i].LinesRemoved > files[j].LinesAdded+files[j].LinesRemoved
+	})
+	var names []string
+	for _, f := range files[:min(n, len(files))] {
+		names = append(names, fmt.Sprintf("%s (%d commits)", f.Path, f.Commits))
+	}
+	return names
+}
+
+// changeSetFromProto converts the collector's change set for the stages,
+// which receive it as JSON
+func changeSetFromProto(cs *collectorpb.ChangeSet) *changeset.ChangeSet {
+	if cs == nil {
+		return nil
+	}
+	out := &changeset.ChangeSet{
+		BaseRef:    cs.BaseRef,
+		BaseCommit: cs.BaseCommit,
+		HeadCommit: cs.HeadCommit,
+		MergeBase:  cs.MergeBase,
+	}
+	for _, f := range cs.Files {
+		file := changeset.File{Path: f.Path, OldPath: f.OldPath, Status: f.Status, Binary: f.Binary}
+		for _, h := range f.Hunks {
+			file.Hunks = append(file.Hunks, changeset.Hunk{
+				OldStart: int(h.OldStart),
+				OldLines: int(h.OldLines),
+				NewStart: int(h.NewStart),
+				NewLines: int(h.NewLines),
+			})
+		}
+		out.Files = append(out.Files, file)
+	}
+	return out
+}
+
+// loadRepoConfig parses the .unarya.yml returned by the collector, falling
+// back to the defaults when the repository has none
+func (s *OrchestratorServer) loadRepoConfig(collected *collectorpb.CollectorResponse) (*repoconfig.Config, error) {
+	if collected.RepoConfig == "" {
+		return repoconfig.Decode("")
+	}
+	cfg, err := repoconfig.Parse([]byte(collected.RepoConfig))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Orchestrator] ✓ Loaded %s", repoconfig.FileName)
+	return cfg, nil
+}
+
+// failConfig reports an invalid .unarya.yml. Schema problems are listed in
+// the response rather than returned as an RPC error.
+func (s *OrchestratorServer) failConfig(err error) (*orchestratorpb.PipelineResponse, error) {
+	var verr *repoconfig.ValidationError
+	if !errors.As(err, &verr) {
+		return s.fail("config", err)
+	}
+	log.Printf("[ERROR] %v", verr)
 	return &orchestratorpb.PipelineResponse{
-		Status:  "success",
-		Details: details,
+		Status:       "failed",
+		Details:      verr.Error(),
+		ConfigErrors: verr.Problems,
 	}, nil
 }
 
@@ -90,11 +590,103 @@ func (s *OrchestratorServer) fail(stage string, err error) (*orchestratorpb.Pipe
 	}, err
 }
 
+// resumeJobs re-runs jobs that were queued or interrupted when the previous
+// process shut down. Results are recorded in the job store only. Jobs that
+// needed a token, SSH key or credentials in their URL fail instead, since
+// those are not persisted.
+func (s *OrchestratorServer) resumeJobs() {
+	pending := s.jobs.List(orchestrator.JobQueued, orchestrator.JobInterrupted, orchestrator.JobRunning)
+	for _, job := range pending {
+		if job.Request.Credentials && job.Request.Token == "" && job.Request.SSHKey == "" {
+			// Re-running it would only fail to authenticate
+			s.jobs.SetStatus(job.ID, orchestrator.JobFailed,
+				"credentials were not persisted across the restart; resubmit the job")
+			log.Printf("[Orchestrator] Not resuming job %s: its credentials were not persisted", job.ID)
+			continue
+		}
+		if err := s.intake.Begin(); err != nil {
+			return
+		}
+		job.Status = orchestrator.JobQueued
+		job.Stage = ""
+		s.jobs.Put(job)
+		log.Printf("[Orchestrator] Resuming job %s for repo: %s", job.ID, utils.RedactURL(job.Request.RepositoryURL))
+
+		go func(job orchestrator.Job) {
+			defer s.intake.End()
+			ctx, cancel := s.intake.JobContext(context.Background())
+			defer cancel()
+			s.runJob(ctx, job)
+		}(job)
+	}
+}
+
+// checkpoint persists the job store and ends every WatchJob stream;
+// registered as a shutdown hook
+func (s *OrchestratorServer) checkpoint(ctx context.Context) {
+	defer s.jobs.CloseWatchers()
+	queued := len(s.jobs.List(orchestrator.JobQueued, orchestrator.JobInterrupted))
+	if err := s.jobs.Checkpoint(); err != nil {
+		log.Printf("[Orchestrator] ❌ Failed to checkpoint jobs: %v", err)
+		return
+	}
+	log.Printf("[Orchestrator] Checkpointed jobs (%d pending)", queued)
+}
+
+// newOrchestratorServer connects to every stage service and opens the job store
+func newOrchestratorServer(intake *sharedgrpc.Intake) (*OrchestratorServer, error) {
+	jobs, err := orchestrator.NewJobStore(getEnv("ORCHESTRATOR_JOB_STORE", "data/jobs.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	maxConcurrent, err := strconv.Atoi(getEnv("MAX_CONCURRENT_PIPELINES", "4"))
+	if err != nil || maxConcurrent < 1 {
+		return nil, fmt.Errorf("invalid MAX_CONCURRENT_PIPELINES: %q", os.Getenv("MAX_CONCURRENT_PIPELINES"))
+	}
+
+	collectorConn, err := sharedgrpc.NewGRPCClient(getEnv("COLLECTOR_ADDR", "collector:50052"))
+	if err != nil {
+		return nil, err
+	}
+	parserConn, err := sharedgrpc.NewGRPCClient(getEnv("PARSER_ADDR", "parser:50053"))
+	if err != nil {
+		return nil, err
+	}
+	aiConn, err := sharedgrpc.NewGRPCClient(getEnv("AI_ADDR", "ai_model:6000"))
+	if err != nil {
+		return nil, err
+	}
+	securityConn, err := sharedgrpc.NewGRPCClient(getEnv("SECURITY_SCAN_ADDR", "security_scan:50054"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrchestratorServer{
+		collectorClient: collectorpb.NewCollectorServiceClient(collectorConn),
+		parserClient:    parserpb.NewParserServiceClient(parserConn),
+		aiClient:        aipb.NewAIServiceClient(aiConn),
+		securityClient:  security_scanpb.NewSecurityScanServiceClient(securityConn),
+		jobs:            jobs,
+		intake:          intake,
+		slots:           make(chan struct{}, maxConcurrent),
+		stageConns: map[string]*grpc.ClientConn{
+			orchestrator.StageCollector:    collectorConn,
+			orchestrator.StageParser:       parserConn,
+			orchestrator.StageAI:           aiConn,
+			orchestrator.StageSecurityScan: securityConn,
+		},
+	}, nil
+}
+
 // StartOrchestrator launches the orchestrator gRPC server
 func StartOrchestrator() error {
-	port := os.Getenv("ORCHESTRATOR_PORT")
-	if port == "" {
-		port = "50051"
+	cfg := config.Load()
+	port := getEnv("ORCHESTRATOR_PORT", "50051")
+	// Requests are validated here before they reach the collector
+	collector.SetGitHosts(cfg.GitAllowedHosts...)
+	if err := collector.AllowPrivateNetworks(cfg.AllowedPrivateNetworks...); err != nil {
+		return fmt.Errorf("invalid ALLOWED_PRIVATE_NETWORKS: %w", err)
 	}
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
@@ -102,11 +694,37 @@ func StartOrchestrator() error {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
-	grpcServer := grpc.NewServer()
-	orchestratorpb.RegisterOrchestratorServiceServer(grpcServer, &OrchestratorServer{})
+	intake := sharedgrpc.NewIntake()
+	// Watching a job starts no work, so it is served while intake is closed
+	intake.Exempt(orchestratorpb.OrchestratorService_WatchJob_FullMethodName)
+	orchestratorSrv, err := newOrchestratorServer(intake)
+	if err != nil {
+		return err
+	}
+
+	auditor, err := sharedgrpc.OpenAuditor(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer auditor.Close()
+
+	grpcServer := sharedgrpc.NewServer(intake, auditor, sharedgrpc.NewAdminPolicy(cfg))
+	orchestratorpb.RegisterOrchestratorServiceServer(grpcServer, orchestratorSrv)
+	orchestratorSrv.resumeJobs()
 
 	log.Printf("[Unarya] 🚀 Orchestrator service started on port %s", port)
-	return grpcServer.Serve(lis)
+	return sharedgrpc.ServeWithShutdown(grpcServer, lis, sharedgrpc.ShutdownOptions{
+		Intake:       intake,
+		DrainTimeout: cfg.DrainTimeout,
+		OnShutdown:   []func(context.Context){orchestratorSrv.checkpoint},
+	})
+}
+
+func getEnv(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
 }
 
 func main() {
@@ -1,4 +1,4 @@
-module parser
+module github.com/unarya/unarya/cmd/parser
 
 require (
 	github.com/unarya/unarya v0.11.0-alpha.1
@@ -1,19 +1,27 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"go/ast"
 	"go/parser"
 	"go/token"
+	"io/fs"
 	"log"
 	"net"
 	"os"
 	"path/filepath"
 	"strings"
 
+	codeparser "github.com/unarya/unarya/internal/parser"
+	"github.com/unarya/unarya/internal/shared/artifact"
+	"github.com/unarya/unarya/internal/shared/changeset"
+	"github.com/unarya/unarya/internal/shared/config"
+	sharedgrpc "github.com/unarya/unarya/internal/shared/grpc"
+	"github.com/unarya/unarya/internal/shared/repoconfig"
 	"github.com/unarya/unarya/lib/proto/pb/parserpb"
-	"google.golang.org/grpc"
 )
 
 // ===============================================
@@ -22,6 +30,8 @@ import (
 
 type ParserServer struct {
 	parserpb.UnimplementedParserServiceServer
+
+	artifacts artifact.Store // nil when sources are read from a shared volume
 }
 
 // ===============================================
@@ -38,11 +48,27 @@ func main() {
 		log.Fatalf("❌ Failed to listen on port %s: %v", port, err)
 	}
 
-	grpcServer := grpc.NewServer()
-	parserpb.RegisterParserServiceServer(grpcServer, &ParserServer{})
+	cfg := config.Load()
+	artifacts, err := artifact.Open(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to open artifact store: %v", err)
+	}
+	intake := sharedgrpc.NewIntake()
+	auditor, err := sharedgrpc.OpenAuditor(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to open audit log: %v", err)
+	}
+	defer auditor.Close()
+
+	grpcServer := sharedgrpc.NewServer(intake, auditor, sharedgrpc.NewAdminPolicy(cfg))
+	parserpb.RegisterParserServiceServer(grpcServer, &ParserServer{artifacts: artifacts})
 
 	log.Printf("🚀 Parser service started on port %s", port)
-	if err := grpcServer.Serve(lis); err != nil {
+	err = sharedgrpc.ServeWithShutdown(grpcServer, lis, sharedgrpc.ShutdownOptions{
+		Intake:       intake,
+		DrainTimeout: cfg.DrainTimeout,
+	})
+	if err != nil {
 		log.Fatalf("❌ Failed to serve: %v", err)
 	}
 }
@@ -51,7 +77,11 @@ func main() {
 // Core RPC Handler
 // ===============================================
 func (p *ParserServer) ParseCode(ctx context.Context, req *parserpb.ParseRequest) (*parserpb.ParseResponse, error) {
-	sourcePath := strings.TrimSpace(req.SourcePath)
+	sourcePath, cleanup, err := artifact.Materialize(ctx, p.artifacts, req.ArtifactDigest, strings.TrimSpace(req.SourcePath))
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
 	if sourcePath == "" {
 		return nil, fmt.Errorf("source_path is empty")
 	}
@@ -64,28 +94,55 @@ func (p *ParserServer) ParseCode(ctx context.Context, req *parserpb.ParseRequest
 		return nil, fmt.Errorf("source path must be a directory: %s", sourcePath)
 	}
 
+	repoCfg, err := repoconfig.Decode(req.RepoConfig)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := changeset.Decode(req.ChangeSet)
+	if err != nil {
+		return nil, err
+	}
+
 	log.Printf("🧩 [Parser] Parsing source directory: %s", sourcePath)
 
-	// 1️⃣ Detect language
-	lang := DetectLanguage(sourcePath)
-	log.Printf("🗣️  Detected language: %s", lang)
+	// 1️⃣ Detect language, unless the repository pins it
+	lang := repoCfg.Parser.Language
+	if lang != "" {
+		log.Printf("🗣️  Language set by %s: %s", repoconfig.FileName, lang)
+	} else {
+		lang = DetectLanguage(sourcePath, repoCfg)
+		log.Printf("🗣️  Detected language: %s", lang)
+	}
 
 	// 2️⃣ Extract dependencies
-	deps := ExtractDependencies(sourcePath)
+	deps := ExtractDependencies(sourcePath, repoCfg)
 	log.Printf("📦 Found %d dependency files", len(deps))
 
-	// 3️⃣ Build AST / code structure
-	astData := BuildAST(sourcePath, lang)
+	// 3️⃣ Build AST / code structure, of the changed files only for a pull request
+	astData := BuildAST(sourcePath, lang, repoCfg, changes)
 
 	// 4️⃣ Convert to JSON structure
 	codeStructure := GenerateCodeRepresentation(astData)
 
+	// 5️⃣ Measure each source file, keyed like the collector's manifest
+	metrics := MeasureFiles(sourcePath, repoCfg, changes)
+	log.Printf("📏 Measured %d source files", len(metrics))
+
 	resp := &parserpb.ParseResponse{
 		Language:       lang,
 		Dependencies:   deps,
 		CodeStructure:  codeStructure,
 		Representation: "json",
 	}
+	for _, m := range metrics {
+		resp.FileMetrics = append(resp.FileMetrics, &parserpb.FileMetrics{
+			Path:       m.Path,
+			Lines:      int32(m.TotalLines),
+			Functions:  int32(m.Functions),
+			Classes:    int32(m.Classes),
+			Complexity: int32(m.Complexity),
+		})
+	}
 
 	log.Printf("✅ [Parser] Completed parsing (%s)", lang)
 	return resp, nil
@@ -95,8 +152,17 @@ func (p *ParserServer) ParseCode(ctx context.Context, req *parserpb.ParseRequest
 // Helpers
 // ===============================================
 
+// sourceExts are the extensions of the files MeasureFiles reports
+var sourceExts = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".ts": true, ".java": true,
+	".cpp": true, ".c": true, ".hpp": true, ".rs": true, ".php": true, ".rb": true,
+}
+
+// maxMeasuredFileSize skips generated or vendored blobs
+const maxMeasuredFileSize = 4 << 20
+
 // DetectLanguage identifies the main programming language in a directory
-func DetectLanguage(sourcePath string) string {
+func DetectLanguage(sourcePath string, repoCfg *repoconfig.Config) string {
 	files, err := os.ReadDir(sourcePath)
 	if err != nil {
 		return "Unknown"
@@ -104,7 +170,7 @@ func DetectLanguage(sourcePath string) string {
 
 	extCount := make(map[string]int)
 	for _, f := range files {
-		if f.IsDir() {
+		if f.IsDir() || repoCfg.Ignored(f.Name()) {
 			continue
 		}
 		ext := filepath.Ext(f.Name())
@@ -146,7 +212,7 @@ func DetectLanguage(sourcePath string) string {
 }
 
 // ExtractDependencies scans common dependency/config files (go.mod, package.json, etc.)
-func ExtractDependencies(sourcePath string) []string {
+func ExtractDependencies(sourcePath string, repoCfg *repoconfig.Config) []string {
 	var deps []string
 	depFiles := []string{
 		"go.mod", "package.json", "requirements.txt",
@@ -154,6 +220,9 @@ func ExtractDependencies(sourcePath string) []string {
 	}
 
 	for _, file := range depFiles {
+		if repoCfg.Ignored(file) {
+			continue
+		}
 		fullPath := filepath.Join(sourcePath, file)
 		if data, err := os.ReadFile(fullPath); err == nil {
 			deps = append(deps, fmt.Sprintf("%s: %d bytes", file, len(data)))
@@ -162,14 +231,16 @@ func ExtractDependencies(sourcePath string) []string {
 	return deps
 }
 
-// BuildAST builds a basic AST (only implemented for Go for now)
-func BuildAST(sourcePath, lang string) interface{} {
+// BuildAST builds a basic AST (only implemented for Go for now). With a
+// change set only changed files are parsed.
+func BuildAST(sourcePath, lang string, repoCfg *repoconfig.Config, changes *changeset.ChangeSet) interface{} {
 	if lang != "Go" {
 		return map[string]any{"note": fmt.Sprintf("AST for %s not implemented", lang)}
 	}
 
 	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, sourcePath, nil, parser.ParseComments)
+	keep := func(fi os.FileInfo) bool { return !repoCfg.Ignored(fi.Name()) && changes.Changed(fi.Name()) }
+	pkgs, err := parser.ParseDir(fset, sourcePath, keep, parser.ParseComments)
 	if err != nil {
 		return map[string]any{"error": err.Error()}
 	}
@@ -185,3 +256,89 @@ func GenerateCodeRepresentation(astData interface{}) string {
 	}
 	return string(jsonData)
 }
+
+// MeasureFiles reports metrics for every source file below sourcePath, or
+// for the changed ones with a change set. Paths are relative to sourcePath
+// with forward slashes, so they join with the collector's manifest and file
+// history. Only Go files get more than a line count.
+func MeasureFiles(sourcePath string, repoCfg *repoconfig.Config, changes *changeset.ChangeSet) []codeparser.CodeMetrics {
+	var metrics []codeparser.CodeMetrics
+	filepath.WalkDir(sourcePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(sourcePath, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if d.Name() == ".git" || repoCfg.Ignored(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !sourceExts[filepath.Ext(rel)] || repoCfg.Ignored(rel) || !changes.Changed(rel) {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxMeasuredFileSize {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		m := codeparser.CodeMetrics{Path: rel, TotalFiles: 1, TotalLines: countLines(data)}
+		if filepath.Ext(rel) == ".go" {
+			measureGo(&m, data)
+		}
+		metrics = append(metrics, m)
+		return nil
+	})
+	return metrics
+}
+
+func countLines(data []byte) int {
+	n := bytes.Count(data, []byte("\n"))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// measureGo counts functions, struct types and cyclomatic complexity: one
+// per function plus one per branch and short-circuit operator
+func measureGo(m *codeparser.CodeMetrics, src []byte) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			m.Functions++
+			m.Complexity++
+		case *ast.FuncLit:
+			m.Complexity++
+		case *ast.TypeSpec:
+			if _, ok := n.Type.(*ast.StructType); ok {
+				m.Classes++
+			}
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			m.Complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				m.Complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				m.Complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				m.Complexity++
+			}
+		}
+		return true
+	})
+}
@@ -1,4 +1,4 @@
-module security_scan
+module github.com/unarya/unarya/cmd/security_scan
 
 require (
 	github.com/unarya/unarya v0.11.0-alpha.1
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -11,14 +12,27 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/unarya/unarya/internal/shared/artifact"
+	"github.com/unarya/unarya/internal/shared/changeset"
+	"github.com/unarya/unarya/internal/shared/config"
+	sharedgrpc "github.com/unarya/unarya/internal/shared/grpc"
+	"github.com/unarya/unarya/internal/shared/repoconfig"
 	"github.com/unarya/unarya/lib/proto/pb/security_scanpb"
-
-	"google.golang.org/grpc"
 )
 
+// ruleSetSeverity is the severity reported for findings of each rule set
+var ruleSetSeverity = map[string]string{
+	"secrets":         "critical",
+	"vulnerabilities": "high",
+	"dependencies":    "medium",
+	"permissions":     "low",
+}
+
 // SecurityScannerServer implements security_scanpb.SecurityScanServiceServer
 type SecurityScannerServer struct {
 	security_scanpb.UnimplementedSecurityScanServiceServer
+
+	artifacts artifact.Store // nil when sources are read from a shared volume
 }
 
 // main starts the gRPC security scanner service
@@ -33,28 +47,70 @@ func main() {
 		log.Fatalf("❌ Failed to listen on port %s: %v", port, err)
 	}
 
-	s := grpc.NewServer()
-	security_scanpb.RegisterSecurityScanServiceServer(s, &SecurityScannerServer{})
+	cfg := config.Load()
+	artifacts, err := artifact.Open(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to open artifact store: %v", err)
+	}
+	intake := sharedgrpc.NewIntake()
+	auditor, err := sharedgrpc.OpenAuditor(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to open audit log: %v", err)
+	}
+	defer auditor.Close()
+
+	s := sharedgrpc.NewServer(intake, auditor, sharedgrpc.NewAdminPolicy(cfg))
+	security_scanpb.RegisterSecurityScanServiceServer(s, &SecurityScannerServer{artifacts: artifacts})
 
 	log.Printf("🛡️  Security Scan service started on port %s", port)
-	if err := s.Serve(lis); err != nil {
+	err = sharedgrpc.ServeWithShutdown(s, lis, sharedgrpc.ShutdownOptions{
+		Intake:       intake,
+		DrainTimeout: cfg.DrainTimeout,
+	})
+	if err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
 
 // ScanForVulnerabilities performs static code analysis
 func (s *SecurityScannerServer) ScanForVulnerabilities(ctx context.Context, req *security_scanpb.ScanRequest) (*security_scanpb.ScanResponse, error) {
-	sourcePath := req.SourcePath
+	sourcePath, cleanup, err := artifact.Materialize(ctx, s.artifacts, req.ArtifactDigest, req.SourcePath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("source path not found: %s", sourcePath)
 	}
 
-	log.Printf("🔍 Scanning source at %s for vulnerabilities", sourcePath)
+	repoCfg, err := repoconfig.Decode(req.RepoConfig)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := changeset.Decode(req.ChangeSet)
+	if err != nil {
+		return nil, err
+	}
 
-	secrets := DetectSecrets(sourcePath)
-	depIssues := CheckDependencies(sourcePath)
-	permIssues := ValidatePermissions(sourcePath)
-	vulnPatterns := DetectCommonVulns(sourcePath)
+	if changes != nil {
+		log.Printf("🔍 Scanning %d changed files at %s for vulnerabilities", len(changes.Paths()), sourcePath)
+	} else {
+		log.Printf("🔍 Scanning source at %s for vulnerabilities", sourcePath)
+	}
+
+	var secrets, depIssues, permIssues, vulnPatterns []string
+	if shouldRun(repoCfg, "secrets") {
+		secrets = DetectSecrets(sourcePath, repoCfg, changes)
+	}
+	if shouldRun(repoCfg, "dependencies") {
+		depIssues = CheckDependencies(sourcePath, repoCfg, changes)
+	}
+	if shouldRun(repoCfg, "permissions") {
+		permIssues = ValidatePermissions(sourcePath, repoCfg, changes)
+	}
+	if shouldRun(repoCfg, "vulnerabilities") {
+		vulnPatterns = DetectCommonVulns(sourcePath, repoCfg, changes)
+	}
 	report := GenerateSecurityReport(secrets, depIssues, permIssues, vulnPatterns)
 
 	return &security_scanpb.ScanResponse{
@@ -63,36 +119,79 @@ func (s *SecurityScannerServer) ScanForVulnerabilities(ctx context.Context, req
 	}, nil
 }
 
+// shouldRun reports whether a rule set is enabled by the repository config
+// and its findings meet the configured severity threshold
+func shouldRun(repoCfg *repoconfig.Config, ruleSet string) bool {
+	return repoCfg.RuleSetEnabled(ruleSet) && repoCfg.MeetsThreshold(ruleSetSeverity[ruleSet])
+}
+
+// walkFiles calls fn for every file under sourcePath that is not ignored by
+// the repository config and, for a pull request, was changed. Ignored
+// directories are not descended into. rel is slash-separated.
+func walkFiles(sourcePath string, repoCfg *repoconfig.Config, changes *changeset.ChangeSet, fn func(path, rel string, info os.FileInfo)) {
+	filepath.Walk(sourcePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(sourcePath, path)
+		if rel != "." && repoCfg.Ignored(rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() && changes.Changed(filepath.ToSlash(rel)) {
+			fn(path, filepath.ToSlash(rel), info)
+		}
+		return nil
+	})
+}
+
+// changedMatches returns the matches of re that start on a changed line of
+// the file; all of them without a change set
+func changedMatches(re *regexp.Regexp, data []byte, rel string, changes *changeset.ChangeSet) []string {
+	var matches []string
+	for _, loc := range re.FindAllIndex(data, -1) {
+		line := 1 + bytes.Count(data[:loc[0]], []byte("\n"))
+		if changes.LineChanged(rel, line) {
+			matches = append(matches, string(data[loc[0]:loc[1]]))
+		}
+	}
+	return matches
+}
+
 // DetectSecrets scans files for hardcoded secrets
-func DetectSecrets(sourcePath string) []string {
+func DetectSecrets(sourcePath string, repoCfg *repoconfig.Config, changes *changeset.ChangeSet) []string {
 	var secrets []string
 	patterns := []*regexp.Regexp{
 		regexp.MustCompile(`(?i)(api[_-]?key|secret|token|password)["'\s:=]+[A-Za-z0-9-_]{8,}`),
 		regexp.MustCompile(`(?i)(aws_access_key_id|aws_secret_access_key)\s*=\s*[A-Za-z0-9/+]{20,}`),
 	}
 
-	filepath.Walk(sourcePath, func(path string, info os.FileInfo, err error) error {
-		if info.IsDir() || strings.Contains(path, "vendor") {
-			return nil
+	walkFiles(sourcePath, repoCfg, changes, func(path, rel string, info os.FileInfo) {
+		if strings.Contains(path, "vendor") {
+			return
 		}
 		data, _ := os.ReadFile(path)
 		for _, p := range patterns {
-			if matches := p.FindAllString(string(data), -1); len(matches) > 0 {
+			if matches := changedMatches(p, data, rel, changes); len(matches) > 0 {
 				secrets = append(secrets, fmt.Sprintf("%s: %v", path, matches))
 			}
 		}
-		return nil
 	})
 
 	return secrets
 }
 
 // CheckDependencies scans for known vulnerable dependencies
-func CheckDependencies(sourcePath string) []string {
+func CheckDependencies(sourcePath string, repoCfg *repoconfig.Config, changes *changeset.ChangeSet) []string {
 	var issues []string
 	depFiles := []string{"go.mod", "package.json", "requirements.txt", "Cargo.toml", "Gemfile.lock"}
 
 	for _, f := range depFiles {
+		if repoCfg.Ignored(f) || !changes.Changed(f) {
+			continue
+		}
 		fp := filepath.Join(sourcePath, f)
 		if _, err := os.Stat(fp); err == nil {
 			data, _ := os.ReadFile(fp)
@@ -106,22 +205,19 @@ func CheckDependencies(sourcePath string) []string {
 }
 
 // ValidatePermissions checks file permissions and insecure configs
-func ValidatePermissions(sourcePath string) []string {
+func ValidatePermissions(sourcePath string, repoCfg *repoconfig.Config, changes *changeset.ChangeSet) []string {
 	var perms []string
-	filepath.Walk(sourcePath, func(path string, info os.FileInfo, err error) error {
-		if err == nil && !info.IsDir() {
-			mode := info.Mode().Perm()
-			if mode&0002 != 0 { // world-writable
-				perms = append(perms, fmt.Sprintf("❗ Insecure permission: %s (%#o)", path, mode))
-			}
+	walkFiles(sourcePath, repoCfg, changes, func(path, rel string, info os.FileInfo) {
+		mode := info.Mode().Perm()
+		if mode&0002 != 0 { // world-writable
+			perms = append(perms, fmt.Sprintf("❗ Insecure permission: %s (%#o)", path, mode))
 		}
-		return nil
 	})
 	return perms
 }
 
 // DetectCommonVulns scans for SQLi, XSS, CSRF patterns
-func DetectCommonVulns(sourcePath string) []string {
+func DetectCommonVulns(sourcePath string, repoCfg *repoconfig.Config, changes *changeset.ChangeSet) []string {
 	var vulns []string
 	patterns := map[string]*regexp.Regexp{
 		"SQL Injection": regexp.MustCompile(`(?i)SELECT\s+.*\+\s+`),
@@ -129,17 +225,13 @@ func DetectCommonVulns(sourcePath string) []string {
 		"CSRF":          regexp.MustCompile(`(?i)csrf_token.*missing`),
 	}
 
-	filepath.Walk(sourcePath, func(path string, info os.FileInfo, err error) error {
-		if info.IsDir() {
-			return nil
-		}
+	walkFiles(sourcePath, repoCfg, changes, func(path, rel string, info os.FileInfo) {
 		data, _ := os.ReadFile(path)
 		for name, re := range patterns {
-			if re.Match(data) {
+			if len(changedMatches(re, data, rel, changes)) > 0 {
 				vulns = append(vulns, fmt.Sprintf("%s pattern found in %s", name, path))
 			}
 		}
-		return nil
 	})
 	return vulns
 }
@@ -3,60 +3,136 @@ package collector
 import (
 	"archive/tar"
 	"archive/zip"
-	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"io/fs"
+	"log"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
-// CollectFromArchive downloads and extracts a ZIP or TAR.GZ archive.
-func CollectFromArchive(cfg SourceConfig) (*CollectionResult, error) {
+// MaxNestedDepth caps SourceConfig.NestedDepth
+const MaxNestedDepth = 5
+
+// nestedSuffix names the directory a nested archive is unpacked into
+const nestedSuffix = ".contents"
+
+// CollectFromArchive downloads an archive and extracts it. The format is
+// detected from the content, not the URL. With NestedDepth > 0, archives
+// found inside are unpacked next to themselves, up to that many levels.
+func CollectFromArchive(ctx context.Context, cfg SourceConfig) (_ *CollectionResult, err error) {
 	if cfg.URL == "" {
 		return nil, fmt.Errorf("archive url is empty")
 	}
-	if cfg.LocalPath == "" {
-		cfg.LocalPath = filepath.Join(os.TempDir(), "collector_archive")
+	cleanup, err := prepareWorkspace(&cfg, "collector-archive")
+	if err != nil {
+		return nil, err
 	}
+	defer cleanup(&err)
 
-	tmpFile := filepath.Join(os.TempDir(), filepath.Base(cfg.URL))
-	defer os.Remove(tmpFile)
-
-	resp, err := http.Get(cfg.URL)
+	// The archive is kept outside the workspace so it is not collected itself
+	dl, err := DefaultDownloader.Fetch(ctx, cfg.URL, "", cfg.SHA256)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download archive: %w", err)
 	}
-	defer resp.Body.Close()
+	defer os.Remove(dl.Path)
+	return CollectFromArchiveFile(ctx, cfg, dl.Path, "")
+}
 
-	out, err := os.Create(tmpFile)
+// CollectFromArchiveFile extracts an archive that is already on disk, such
+// as an upload, into the workspace. An empty format is detected from the
+// content; a declared format must match the content.
+func CollectFromArchiveFile(ctx context.Context, cfg SourceConfig, path, format string) (_ *CollectionResult, err error) {
+	detected, err := DetectFormat(path)
 	if err != nil {
 		return nil, err
 	}
-	defer out.Close()
+	if format != "" {
+		declared, err := NormalizeFormat(format)
+		if err != nil {
+			return nil, err
+		}
+		if declared != detected {
+			return nil, fmt.Errorf("declared format %s does not match content (%s)", declared, detected)
+		}
+	}
 
-	if _, err = io.Copy(out, resp.Body); err != nil {
+	cleanup, err := prepareWorkspace(&cfg, "collector-archive")
+	if err != nil {
 		return nil, err
 	}
+	defer cleanup(&err)
 
-	if strings.HasSuffix(cfg.URL, ".zip") {
-		if err := extractZip(tmpFile, cfg.LocalPath); err != nil {
-			return nil, err
+	x, err := newExtractor(ctx, cfg.LocalPath, DefaultExtractLimits, fileSize(path))
+	if err != nil {
+		return nil, err
+	}
+	if err := extractArchive(x, path, detected); err != nil {
+		return nil, err
+	}
+	if err := extractNested(x, min(cfg.NestedDepth, MaxNestedDepth)); err != nil {
+		return nil, err
+	}
+	return scanFiles(ctx, cfg, cfg.LocalPath)
+}
+
+// extractArchive unpacks src, already identified as format, into x.dest
+func extractArchive(x *extractor, src, format string) error {
+	if format == FormatZip {
+		return extractZip(x, src)
+	}
+	return extractTar(x, src, format)
+}
+
+// extractNested unpacks archives found below x.dest into sibling
+// directories, recursing up to depth levels. Files that look like archives
+// but fail to decode are left as they are; extraction violations abort.
+func extractNested(x *extractor, depth int) error {
+	if depth <= 0 {
+		return nil
+	}
+
+	type found struct{ path, format string }
+	var archives []found
+	err := filepath.WalkDir(x.dest, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
 		}
-	} else if strings.HasSuffix(cfg.URL, ".tar.gz") || strings.HasSuffix(cfg.URL, ".tgz") {
-		if err := extractTarGz(tmpFile, cfg.LocalPath); err != nil {
-			return nil, err
+		if format, err := DetectFormat(path); err == nil {
+			archives = append(archives, found{path, format})
 		}
-	} else {
-		return nil, fmt.Errorf("unsupported archive format")
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return scanFiles(cfg.LocalPath)
+	for _, a := range archives {
+		nx, err := x.nested(a.path + nestedSuffix)
+		if err != nil {
+			return err
+		}
+		err = extractArchive(nx, a.path, a.format)
+		var extractErr *ExtractError
+		switch {
+		case errors.As(err, &extractErr), x.ctx.Err() != nil:
+			return err
+		case err != nil:
+			log.Printf("[Collector] Skipping nested archive %s: %v", a.path, err)
+			os.RemoveAll(nx.dest)
+			continue
+		}
+		if err := extractNested(nx, depth-1); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// extractZip extracts ZIP archives.
-func extractZip(src, dest string) error {
+// extractZip extracts ZIP archives within the extractor's limits.
+func extractZip(x *extractor, src string) error {
 	r, err := zip.OpenReader(src)
 	if err != nil {
 		return err
@@ -64,49 +140,61 @@ func extractZip(src, dest string) error {
 	defer r.Close()
 
 	for _, f := range r.File {
-		fpath := filepath.Join(dest, f.Name)
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(fpath, os.ModePerm)
-			continue
-		}
-		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
+		if err := extractZipEntry(x, f); err != nil {
 			return err
 		}
-		out, err := os.Create(fpath)
+	}
+	return nil
+}
+
+func extractZipEntry(x *extractor, f *zip.File) error {
+	mode := f.Mode()
+	switch {
+	case mode.IsDir():
+		return x.dir(f.Name)
+	case mode&os.ModeSymlink != 0:
+		rc, err := f.Open()
 		if err != nil {
 			return err
 		}
-		rc, err := f.Open()
+		defer rc.Close()
+		link, err := io.ReadAll(io.LimitReader(rc, 4096))
 		if err != nil {
-			out.Close()
 			return err
 		}
-		_, err = io.Copy(out, rc)
-		out.Close()
-		rc.Close()
+		return x.symlink(f.Name, string(link))
+	case mode.IsRegular():
+		// Reject declared sizes early; the actual bytes are counted as well
+		if x.limits.MaxFileBytes > 0 && f.UncompressedSize64 > uint64(x.limits.MaxFileBytes) {
+			return &ExtractError{Kind: ViolationFileSize, Entry: f.Name, Detail: fmt.Sprintf("entry declares %d bytes, limit is %d", f.UncompressedSize64, x.limits.MaxFileBytes)}
+		}
+		rc, err := f.Open()
 		if err != nil {
 			return err
 		}
+		defer rc.Close()
+		return x.file(f.Name, rc, mode, int64(f.CompressedSize64))
+	default:
+		return nil // devices, pipes and sockets are skipped
 	}
-	return nil
 }
 
-// extractTarGz extracts TAR.GZ archives.
-func extractTarGz(src, dest string) error {
+// extractTar extracts plain or compressed TAR archives within the
+// extractor's limits.
+func extractTar(x *extractor, src, format string) error {
 	file, err := os.Open(src)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	gzr, err := gzip.NewReader(file)
+	r, closeDecoder, err := decompress(format, file)
 	if err != nil {
 		return err
 	}
-	defer gzr.Close()
-
-	tr := tar.NewReader(gzr)
+	defer closeDecoder()
 
+	tr := tar.NewReader(r)
 	for {
 		header, err := tr.Next()
 		if err == io.EOF {
@@ -116,23 +204,29 @@ func extractTarGz(src, dest string) error {
 			return err
 		}
 
-		target := filepath.Join(dest, header.Name)
-
 		switch header.Typeflag {
 		case tar.TypeDir:
-			os.MkdirAll(target, os.ModePerm)
+			err = x.dir(header.Name)
 		case tar.TypeReg:
-			os.MkdirAll(filepath.Dir(target), os.ModePerm)
-			out, err := os.Create(target)
-			if err != nil {
-				return err
-			}
-			if _, err := io.Copy(out, tr); err != nil {
-				out.Close()
-				return err
-			}
-			out.Close()
+			err = x.file(header.Name, tr, header.FileInfo().Mode(), 0)
+		case tar.TypeSymlink:
+			err = x.symlink(header.Name, header.Linkname)
+		case tar.TypeLink:
+			err = x.hardlink(header.Name, header.Linkname)
+		default:
+			// devices, pipes and metadata entries are skipped
+		}
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,248 @@
+package collector
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// File classes reported in FileInfo.Class
+const (
+	ClassSource        = "source"
+	ClassTest          = "test"
+	ClassGenerated     = "generated"
+	ClassVendored      = "vendored"
+	ClassBinary        = "binary"
+	ClassDocumentation = "documentation"
+	ClassConfig        = "config"
+	ClassOther         = "other" // text files that fit no other class
+)
+
+// sniffSize is how much of a file is read to classify it
+const sniffSize = 8000
+
+// languageByExt maps lower-case file extensions to languages. Names follow
+// the ones the parser reports.
+var languageByExt = map[string]string{
+	".go":    "Go",
+	".py":    "Python",
+	".pyi":   "Python",
+	".js":    "JavaScript",
+	".mjs":   "JavaScript",
+	".cjs":   "JavaScript",
+	".jsx":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".kts":   "Kotlin",
+	".scala": "Scala",
+	".c":     "C/C++",
+	".h":     "C/C++",
+	".cc":    "C/C++",
+	".cpp":   "C/C++",
+	".cxx":   "C/C++",
+	".hpp":   "C/C++",
+	".cs":    "C#",
+	".rs":    "Rust",
+	".php":   "PHP",
+	".rb":    "Ruby",
+	".swift": "Swift",
+	".m":     "Objective-C",
+	".sh":    "Shell",
+	".bash":  "Shell",
+	".sql":   "SQL",
+	".html":  "HTML",
+	".htm":   "HTML",
+	".css":   "CSS",
+	".scss":  "CSS",
+	".vue":   "Vue",
+	".dart":  "Dart",
+	".lua":   "Lua",
+	".r":     "R",
+	".proto": "Protocol Buffers",
+	".tf":    "HCL",
+	".md":    "Markdown",
+	".rst":   "reStructuredText",
+	".yml":   "YAML",
+	".yaml":  "YAML",
+	".json":  "JSON",
+	".toml":  "TOML",
+	".xml":   "XML",
+}
+
+// languageByName covers files recognised by name rather than extension
+var languageByName = map[string]string{
+	"Dockerfile":  "Dockerfile",
+	"Makefile":    "Makefile",
+	"Jenkinsfile": "Groovy",
+	"Gemfile":     "Ruby",
+	"Rakefile":    "Ruby",
+}
+
+var vendoredDirs = map[string]bool{
+	"vendor": true, "third_party": true, "third-party": true, "thirdparty": true,
+	"node_modules": true, "bower_components": true, "Godeps": true, "external": true,
+}
+
+var testDirs = map[string]bool{
+	"test": true, "tests": true, "__tests__": true, "spec": true, "testdata": true,
+}
+
+var docDirs = map[string]bool{"doc": true, "docs": true, "documentation": true}
+
+var docExts = map[string]bool{".md": true, ".rst": true, ".adoc": true, ".txt": true, ".rdoc": true}
+
+var docNames = []string{"README", "LICENSE", "LICENCE", "CHANGELOG", "CONTRIBUTING", "AUTHORS", "NOTICE", "COPYING"}
+
+var configExts = map[string]bool{
+	".yml": true, ".yaml": true, ".json": true, ".toml": true, ".ini": true, ".cfg": true,
+	".conf": true, ".env": true, ".properties": true, ".xml": true, ".tf": true, ".lock": true,
+}
+
+var configNames = map[string]bool{
+	"Dockerfile": true, "Makefile": true, "Jenkinsfile": true, "Procfile": true,
+	"go.mod": true, "go.work": true, "requirements.txt": true, "Pipfile": true,
+	"Gemfile": true, "setup.py": true, "setup.cfg": true, ".unarya.yml": true,
+	".gitignore": true, ".gitattributes": true, ".gitmodules": true, ".editorconfig": true,
+	".dockerignore": true, IgnoreFileName: true,
+}
+
+var generatedNames = map[string]bool{
+	"go.sum": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+	"Cargo.lock": true, "poetry.lock": true, "Pipfile.lock": true, "composer.lock": true, "Gemfile.lock": true,
+}
+
+var generatedSuffixes = []string{
+	".pb.go", ".pb.gw.go", "_pb2.py", "_pb2_grpc.py", ".gen.go", "_generated.go", "_string.go",
+	".min.js", ".min.css", ".js.map", ".css.map", ".designer.cs",
+}
+
+var binaryExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".bmp": true, ".ico": true, ".webp": true,
+	".pdf": true, ".zip": true, ".gz": true, ".tgz": true, ".bz2": true, ".xz": true, ".zst": true,
+	".7z": true, ".rar": true, ".jar": true, ".war": true, ".exe": true, ".dll": true, ".so": true,
+	".dylib": true, ".a": true, ".o": true, ".class": true, ".pyc": true, ".wasm": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".mp3": true, ".mp4": true, ".mov": true, ".avi": true, ".wav": true, ".sqlite": true, ".db": true,
+}
+
+// generatedMarkers in a file's first bytes mark it as generated
+var generatedMarkers = [][]byte{[]byte("DO NOT EDIT"), []byte("@generated"), []byte("<auto-generated")}
+
+// classify assigns a file its class and language from its slash-separated
+// path relative to the root and its leading bytes
+func classify(rel, full string, lfsPointer bool) (class, language string) {
+	name := path.Base(rel)
+	ext := strings.ToLower(path.Ext(name))
+	language = languageByName[name]
+	if language == "" {
+		language = languageByExt[ext]
+	}
+
+	dirs := strings.Split(path.Dir(rel), "/")
+	switch {
+	case anyDir(dirs, vendoredDirs):
+		return ClassVendored, language
+	case generatedNames[name] || hasAnySuffix(name, generatedSuffixes):
+		return ClassGenerated, language
+	case lfsPointer || binaryExts[ext]:
+		return ClassBinary, ""
+	}
+
+	head := readHead(full)
+	switch {
+	case bytes.IndexByte(head, 0) >= 0:
+		return ClassBinary, ""
+	case hasGeneratedMarker(head):
+		return ClassGenerated, language
+	case isTestFile(name, dirs):
+		return ClassTest, language
+	case docExts[ext] || isDocName(name) || (anyDir(dirs, docDirs) && !isCode(language)):
+		return ClassDocumentation, language
+	case configExts[ext] || configNames[name] || (strings.HasPrefix(name, ".") && language == ""):
+		return ClassConfig, language
+	case isCode(language):
+		return ClassSource, language
+	default:
+		return ClassOther, language
+	}
+}
+
+// isCode reports whether a language is a programming language rather than
+// a markup, data or documentation format
+func isCode(language string) bool {
+	switch language {
+	case "", "Markdown", "reStructuredText", "YAML", "JSON", "TOML", "XML":
+		return false
+	}
+	return true
+}
+
+func isTestFile(name string, dirs []string) bool {
+	lower := strings.ToLower(name)
+	stem := strings.TrimSuffix(lower, path.Ext(lower))
+	switch {
+	case strings.HasSuffix(lower, "_test.go"),
+		strings.HasPrefix(lower, "test_") && strings.HasSuffix(lower, ".py"),
+		strings.HasSuffix(stem, "_test") && strings.HasSuffix(lower, ".py"),
+		strings.HasSuffix(stem, ".test"), strings.HasSuffix(stem, ".spec"),
+		strings.HasSuffix(stem, "test") && strings.HasSuffix(lower, ".java"),
+		strings.HasSuffix(stem, "_spec") && strings.HasSuffix(lower, ".rb"):
+		return true
+	}
+	return anyDir(dirs, testDirs)
+}
+
+func isDocName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, doc := range docNames {
+		if upper == doc || strings.HasPrefix(upper, doc+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func anyDir(dirs []string, set map[string]bool) bool {
+	for _, d := range dirs {
+		if set[d] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnySuffix(name string, suffixes []string) bool {
+	for _, s := range suffixes {
+		if strings.HasSuffix(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasGeneratedMarker(head []byte) bool {
+	// Markers only count near the top, where generators put them
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	for _, m := range generatedMarkers {
+		if bytes.Contains(head, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func readHead(full string) []byte {
+	f, err := os.Open(full)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	head, _ := io.ReadAll(io.LimitReader(f, sniffSize))
+	return head
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/unarya/unarya/internal/shared/changeset"
+)
+
+// diffBase fetches cfg.BaseRef into a checked-out repository and lists the
+// files and lines changed from its merge base with head, the way a pull
+// request shows them. With a subpath, only changes below it are listed and
+// paths are relative to it.
+func diffBase(ctx context.Context, cfg SourceConfig, head string) (*changeset.ChangeSet, error) {
+	dir := cfg.LocalPath
+	if _, err := runGit(ctx, cfg, dir, "fetch", "--quiet", "--no-tags", "origin", cfg.BaseRef); err != nil {
+		return nil, fmt.Errorf("failed to fetch base %q: %w", cfg.BaseRef, err)
+	}
+	base, err := runGit(ctx, cfg, dir, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return nil, err
+	}
+	mergeBase, err := runGit(ctx, cfg, dir, "merge-base", base, head)
+	if err != nil {
+		return nil, fmt.Errorf("base %q has no common history with %s: %w", cfg.BaseRef, head, err)
+	}
+
+	diff := []string{"diff", "-M", "--no-color", "--no-ext-diff", "--no-textconv"}
+	if cfg.Subpath != "" {
+		diff = append(diff, "--relative="+cfg.Subpath)
+	}
+	nameStatus, err := runGit(ctx, cfg, dir, append(slices.Clone(diff), "--name-status", "-z", mergeBase, head)...)
+	if err != nil {
+		return nil, err
+	}
+	files, err := changeset.ParseNameStatus([]byte(nameStatus))
+	if err != nil {
+		return nil, err
+	}
+	patch, err := runGit(ctx, cfg, dir, append(slices.Clone(diff), "--unified=0", mergeBase, head)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := changeset.AddHunks(files, []byte(patch)); err != nil {
+		return nil, err
+	}
+
+	return &changeset.ChangeSet{
+		BaseRef:    cfg.BaseRef,
+		BaseCommit: base,
+		HeadCommit: head,
+		MergeBase:  mergeBase,
+		Files:      files,
+	}, nil
+}
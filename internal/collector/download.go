@@ -0,0 +1,259 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unarya/unarya/internal/shared/utils"
+)
+
+// ErrDownloadTooLarge is returned when a download exceeds Downloader.MaxBytes.
+var ErrDownloadTooLarge = errors.New("download exceeds the size limit")
+
+// ErrChecksumMismatch is returned when downloaded content does not match
+// the expected SHA-256.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Downloader fetches HTTP(S) sources into temporary files. Interrupted
+// transfers are retried and resumed with Range requests when the server
+// supports them.
+type Downloader struct {
+	Client     *http.Client
+	Timeout    time.Duration // whole download including retries; 0 disables
+	MaxBytes   int64         // largest accepted body; 0 disables
+	Retries    int           // attempts after the first
+	RetryDelay time.Duration // doubled after each attempt
+}
+
+// DefaultDownloader is used by CollectFromURL and CollectFromArchive
+var DefaultDownloader = &Downloader{
+	Client:     httpClient,
+	Timeout:    30 * time.Minute,
+	MaxBytes:   2 << 30,
+	Retries:    3,
+	RetryDelay: time.Second,
+}
+
+// Download describes a fetched file
+type Download struct {
+	Path   string // temporary file holding the content
+	Name   string // file name from Content-Disposition or the URL
+	Size   int64
+	SHA256 string
+}
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Fetch downloads rawURL into a new temporary file in dir (the system temp
+// directory when empty). When sha256 is set the content must match it. The
+// caller owns the returned file; on error nothing is left behind.
+func (d *Downloader) Fetch(ctx context.Context, rawURL, dir, sha256 string) (_ *Download, err error) {
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+
+	f, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		f.Close()
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	dl := &Download{Path: f.Name(), Name: fileNameFromURL(rawURL)}
+	var validator string // ETag or Last-Modified guarding resumed ranges
+	delay := d.RetryDelay
+	for attempt := 0; ; attempt++ {
+		err = d.attempt(ctx, rawURL, f, dl, &validator)
+		if err == nil {
+			break
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) || ctx.Err() != nil || attempt >= d.Retries {
+			return nil, err
+		}
+		log.Printf("[Collector] Retrying download of %s (%d bytes so far): %v", utils.RedactURL(rawURL), dl.Size, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		delay *= 2
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if dl.SHA256, err = utils.HashFile(f.Name()); err != nil {
+		return nil, err
+	}
+	if sha256 != "" && !strings.EqualFold(sha256, dl.SHA256) {
+		return nil, fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, strings.ToLower(sha256), dl.SHA256)
+	}
+	return dl, nil
+}
+
+// attempt issues one GET, resuming after the dl.Size bytes already in f
+func (d *Downloader) attempt(ctx context.Context, rawURL string, f *os.File, dl *Download, validator *string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return &permanentError{err}
+	}
+	if dl.Size > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", dl.Size))
+		if *validator != "" {
+			req.Header.Set("If-Range", *validator)
+		}
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && dl.Size > 0 && rangeStart(resp) == dl.Size:
+		// Resuming
+	case resp.StatusCode == http.StatusOK:
+		// Fresh content, or the server ignored the range: start over
+		if err := restart(f, dl); err != nil {
+			return &permanentError{err}
+		}
+		*validator = resp.Header.Get("ETag")
+		if *validator == "" {
+			*validator = resp.Header.Get("Last-Modified")
+		}
+		if name := dispositionName(resp.Header.Get("Content-Disposition")); name != "" {
+			dl.Name = name
+		} else {
+			dl.Name = fileNameFromURL(resp.Request.URL.String())
+		}
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("GET %s returned %s", utils.RedactURL(rawURL), resp.Status)
+	case resp.StatusCode == http.StatusPartialContent || resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// A range we cannot use; retry from the beginning
+		if err := restart(f, dl); err != nil {
+			return &permanentError{err}
+		}
+		return fmt.Errorf("GET %s returned an unusable range", utils.RedactURL(rawURL))
+	default:
+		return &permanentError{fmt.Errorf("GET %s returned %s", utils.RedactURL(rawURL), resp.Status)}
+	}
+
+	if d.MaxBytes > 0 && resp.ContentLength > 0 && dl.Size+resp.ContentLength > d.MaxBytes {
+		return &permanentError{fmt.Errorf("%w: %d bytes", ErrDownloadTooLarge, dl.Size+resp.ContentLength)}
+	}
+	body := io.Reader(resp.Body)
+	if d.MaxBytes > 0 {
+		body = io.LimitReader(resp.Body, d.MaxBytes-dl.Size+1)
+	}
+	var dst io.Writer = f
+	if r := progressFrom(ctx); r != nil {
+		total := int64(0)
+		if resp.ContentLength > 0 {
+			total = dl.Size + resp.ContentLength
+		}
+		dst = &downloadProgress{w: f, r: r, done: dl.Size, total: total, start: time.Now()}
+		defer r.flush()
+	}
+	n, err := io.Copy(dst, body)
+	dl.Size += n
+	if d.MaxBytes > 0 && dl.Size > d.MaxBytes {
+		return &permanentError{fmt.Errorf("%w: more than %d bytes", ErrDownloadTooLarge, d.MaxBytes)}
+	}
+	if err != nil {
+		return err
+	}
+	if resp.ContentLength > 0 && n < resp.ContentLength {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// downloadProgress reports StageDownload progress as a download is written
+type downloadProgress struct {
+	w           io.Writer
+	r           *progressReporter
+	done, total int64
+	written     int64 // by this attempt, for the rate
+	start       time.Time
+}
+
+func (d *downloadProgress) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	d.done += int64(n)
+	d.written += int64(n)
+	var rate int64
+	if elapsed := time.Since(d.start).Seconds(); elapsed > 0 {
+		rate = int64(float64(d.written) / elapsed)
+	}
+	d.r.report(Progress{
+		Stage:          StageDownload,
+		Percent:        percent(d.done, d.total),
+		Done:           d.done,
+		Total:          d.total,
+		BytesPerSecond: rate,
+	})
+	return n, err
+}
+
+// restart discards what was written so far
+func restart(f *os.File, dl *Download) error {
+	dl.Size = 0
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.Seek(0, io.SeekStart)
+	return err
+}
+
+// rangeStart returns the first byte of a 206 response's Content-Range, or -1
+func rangeStart(resp *http.Response) int64 {
+	cr := strings.TrimPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	start, _, ok := strings.Cut(cr, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// dispositionName returns the file name suggested by a Content-Disposition
+// header, reduced to a safe base name
+func dispositionName(header string) string {
+	if header == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return ""
+	}
+	name := filepath.Base(strings.ReplaceAll(params["filename"], `\`, "/"))
+	if name == "." || name == ".." || name == "/" || strings.HasPrefix(name, ".") {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const estimateTimeout = 10 * time.Second
+
+// EstimateSize guesses how many bytes collecting a source would download,
+// without fetching it. Archives, URLs and images are sized from a HEAD
+// request; git repositories from the GitHub or GitLab API; local directories
+// by walking them; packages from a HEAD request for the artifact their
+// registry lists.
+// It returns 0 when the size cannot be determined.
+func EstimateSize(ctx context.Context, cfg SourceConfig) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, estimateTimeout)
+	defer cancel()
+
+	switch cfg.Type {
+	case "archive", "url", "image":
+		return headContentLength(ctx, cfg.URL)
+	case "git":
+		return estimateRepoSize(ctx, cfg)
+	case "local":
+		return localSize(cfg.URL)
+	case "package":
+		pkg, err := resolvePackage(ctx, cfg)
+		if err != nil {
+			return 0, err
+		}
+		return headContentLength(ctx, pkg.URL)
+	default:
+		return 0, ErrInvalidSourceType
+	}
+}
+
+func headContentLength(ctx context.Context, rawURL string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("HEAD %s failed: %w", rawURL, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HEAD %s returned %s", rawURL, resp.Status)
+	}
+	if resp.ContentLength < 0 {
+		return 0, nil
+	}
+	return resp.ContentLength, nil
+}
+
+// estimateRepoSize asks the hosting provider's API for the repository size
+func estimateRepoSize(ctx context.Context, cfg SourceConfig) (int64, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return 0, err
+	}
+	repoPath := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+
+	switch u.Hostname() {
+	case "github.com":
+		var repo struct {
+			Size int64 `json:"size"` // KiB
+		}
+		apiURL := "https://api.github.com/repos/" + repoPath
+		if err := getJSON(ctx, apiURL, "Authorization", bearer(cfg.Token), &repo); err != nil {
+			return 0, err
+		}
+		return repo.Size * 1024, nil
+
+	case "gitlab.com":
+		var project struct {
+			Statistics struct {
+				RepositorySize int64 `json:"repository_size"`
+			} `json:"statistics"`
+		}
+		apiURL := "https://gitlab.com/api/v4/projects/" + url.PathEscape(repoPath) + "?statistics=true"
+		if err := getJSON(ctx, apiURL, "PRIVATE-TOKEN", cfg.Token, &project); err != nil {
+			return 0, err
+		}
+		return project.Statistics.RepositorySize, nil
+
+	default:
+		return 0, nil
+	}
+}
+
+func getJSON(ctx context.Context, apiURL, authHeader, authValue string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	if authValue != "" {
+		req.Header.Set(authHeader, authValue)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", apiURL, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func bearer(token string) string {
+	if token == "" {
+		return ""
+	}
+	return "Bearer " + token
+}
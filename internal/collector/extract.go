@@ -0,0 +1,297 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractLimits bounds what an archive may unpack to. Zero fields disable
+// the corresponding check.
+type ExtractLimits struct {
+	MaxEntries    int     // files, directories and links
+	MaxTotalBytes int64   // uncompressed bytes across all entries
+	MaxFileBytes  int64   // uncompressed bytes of a single entry
+	MaxRatio      float64 // uncompressed / compressed size
+}
+
+// DefaultExtractLimits are applied by CollectFromArchive
+var DefaultExtractLimits = ExtractLimits{
+	MaxEntries:    100000,
+	MaxTotalBytes: 2 << 30,
+	MaxFileBytes:  512 << 20,
+	MaxRatio:      100,
+}
+
+// ratioFloor is the uncompressed size below which the ratio is not checked,
+// so tiny, highly compressible files are not flagged
+const ratioFloor = 1 << 20
+
+// Extraction violations reported in ExtractError.Kind
+const (
+	ViolationPathTraversal    = "path_traversal"
+	ViolationAbsolutePath     = "absolute_path"
+	ViolationUnsafeLink       = "unsafe_link"
+	ViolationTooManyEntries   = "too_many_entries"
+	ViolationTotalSize        = "total_size"
+	ViolationFileSize         = "file_size"
+	ViolationCompressionRatio = "compression_ratio"
+)
+
+// ExtractError reports an archive entry that violated extraction rules
+type ExtractError struct {
+	Kind   string // one of the Violation constants
+	Entry  string // entry name as stored in the archive
+	Detail string
+}
+
+func (e *ExtractError) Error() string {
+	return fmt.Sprintf("unsafe archive entry %q (%s): %s", e.Entry, e.Kind, e.Detail)
+}
+
+// extractor writes archive entries below dest while enforcing limits
+type extractor struct {
+	ctx        context.Context
+	dest       string
+	limits     ExtractLimits
+	compressed int64 // size of the outermost archive on disk
+	*quota
+}
+
+// quota is shared by an extractor and the ones it creates for nested archives
+type quota struct {
+	entries  int
+	written  int64
+	progress *progressReporter
+	step     string // Progress.Step of the entries
+}
+
+func newExtractor(ctx context.Context, dest string, limits ExtractLimits, compressed int64) (*extractor, error) {
+	abs, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, err
+	}
+	return &extractor{ctx: ctx, dest: abs, limits: limits, compressed: compressed, quota: &quota{progress: progressFrom(ctx)}}, nil
+}
+
+// nested returns an extractor writing below dir that draws on the same quotas
+func (x *extractor) nested(dir string) (*extractor, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	n := *x
+	n.dest = dir
+	return &n, nil
+}
+
+// resolve maps an entry name to a path inside dest, rejecting absolute
+// paths, traversal and paths that pass through a symlink
+func (x *extractor) resolve(name string) (string, error) {
+	slashed := strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(slashed, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" ||
+		(len(slashed) > 1 && slashed[1] == ':') {
+		return "", &ExtractError{Kind: ViolationAbsolutePath, Entry: name, Detail: "absolute paths are not allowed"}
+	}
+	for _, part := range strings.Split(slashed, "/") {
+		if part == ".." {
+			return "", &ExtractError{Kind: ViolationPathTraversal, Entry: name, Detail: "path escapes the workspace"}
+		}
+	}
+
+	target := filepath.Join(x.dest, filepath.FromSlash(slashed))
+	if !x.inside(target) {
+		return "", &ExtractError{Kind: ViolationPathTraversal, Entry: name, Detail: "path escapes the workspace"}
+	}
+	if err := x.checkParents(name, target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+func (x *extractor) inside(path string) bool {
+	rel, err := filepath.Rel(x.dest, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// checkParents refuses to write through a symlink created by an earlier entry
+func (x *extractor) checkParents(name, target string) error {
+	rel, _ := filepath.Rel(x.dest, target)
+	dir := x.dest
+	parts := strings.Split(rel, string(filepath.Separator))
+	for _, part := range parts[:len(parts)-1] {
+		dir = filepath.Join(dir, part)
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return &ExtractError{Kind: ViolationUnsafeLink, Entry: name, Detail: "path passes through a symlink"}
+		}
+	}
+	return nil
+}
+
+// count enforces the entry quota and stops early when the request is
+// cancelled. Entries are reported as StageExtract progress.
+func (x *extractor) count(name string) error {
+	if err := x.ctx.Err(); err != nil {
+		return err
+	}
+	x.entries++
+	if x.limits.MaxEntries > 0 && x.entries > x.limits.MaxEntries {
+		return &ExtractError{Kind: ViolationTooManyEntries, Entry: name, Detail: fmt.Sprintf("archive has more than %d entries", x.limits.MaxEntries)}
+	}
+	x.progress.report(Progress{Stage: StageExtract, Step: x.step, Percent: -1, Done: int64(x.entries)})
+	return nil
+}
+
+func (x *extractor) dir(name string) error {
+	if err := x.count(name); err != nil {
+		return err
+	}
+	target, err := x.resolve(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(target, 0755)
+}
+
+// file copies r to the entry's path. compressed is the entry's own
+// compressed size, or 0 when only the archive-wide ratio can be checked.
+func (x *extractor) file(name string, r io.Reader, mode os.FileMode, compressed int64) error {
+	if err := x.count(name); err != nil {
+		return err
+	}
+	target, err := x.resolve(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	// Never follow or overwrite a link left by an earlier entry
+	if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return &ExtractError{Kind: ViolationUnsafeLink, Entry: name, Detail: "entry overwrites a symlink"}
+	}
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()&0755|0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	// Stop copying as soon as any quota is exceeded rather than after the fact
+	limit := int64(-1)
+	capAt := func(n int64) {
+		if limit < 0 || n < limit {
+			limit = n
+		}
+	}
+	if x.limits.MaxFileBytes > 0 {
+		capAt(x.limits.MaxFileBytes)
+	}
+	if x.limits.MaxTotalBytes > 0 {
+		capAt(x.limits.MaxTotalBytes - x.written)
+	}
+	if x.limits.MaxRatio > 0 && x.compressed > 0 {
+		budget := int64(float64(x.compressed) * x.limits.MaxRatio)
+		if budget < ratioFloor {
+			budget = ratioFloor
+		}
+		capAt(budget - x.written)
+	}
+
+	var n int64
+	if limit >= 0 {
+		n, err = io.Copy(out, io.LimitReader(r, limit+1))
+	} else {
+		n, err = io.Copy(out, r)
+	}
+	x.written += n
+	if err != nil {
+		return err
+	}
+
+	if x.limits.MaxFileBytes > 0 && n > x.limits.MaxFileBytes {
+		return &ExtractError{Kind: ViolationFileSize, Entry: name, Detail: fmt.Sprintf("entry exceeds %d bytes", x.limits.MaxFileBytes)}
+	}
+	if x.limits.MaxTotalBytes > 0 && x.written > x.limits.MaxTotalBytes {
+		return &ExtractError{Kind: ViolationTotalSize, Entry: name, Detail: fmt.Sprintf("archive expands beyond %d bytes", x.limits.MaxTotalBytes)}
+	}
+	if x.exceedsRatio(n, compressed) || x.exceedsRatio(x.written, x.compressed) {
+		return &ExtractError{Kind: ViolationCompressionRatio, Entry: name, Detail: fmt.Sprintf("compression ratio exceeds %.0f:1", x.limits.MaxRatio)}
+	}
+	return nil
+}
+
+func (x *extractor) exceedsRatio(uncompressed, compressed int64) bool {
+	if x.limits.MaxRatio <= 0 || compressed <= 0 || uncompressed < ratioFloor {
+		return false
+	}
+	return float64(uncompressed)/float64(compressed) > x.limits.MaxRatio
+}
+
+// symlink creates a relative symlink whose target stays inside dest
+func (x *extractor) symlink(name, linkname string) error {
+	if err := x.count(name); err != nil {
+		return err
+	}
+	target, err := x.resolve(name)
+	if err != nil {
+		return err
+	}
+	if linkname == "" || filepath.IsAbs(linkname) || strings.HasPrefix(linkname, "/") {
+		return &ExtractError{Kind: ViolationUnsafeLink, Entry: name, Detail: fmt.Sprintf("absolute symlink target %q", linkname)}
+	}
+	dir := filepath.Dir(target)
+	resolved := filepath.Join(dir, filepath.FromSlash(linkname))
+	if !x.inside(resolved) {
+		return &ExtractError{Kind: ViolationUnsafeLink, Entry: name, Detail: fmt.Sprintf("symlink target %q escapes the workspace", linkname)}
+	}
+	// The check above is lexical, but the kernel resolves a ".." after a
+	// symlink through that link. Writing the cleaned target leaves ".."
+	// only at its start, where it climbs real directories (see
+	// checkParents), so chains of links cannot escape either.
+	clean, err := filepath.Rel(dir, resolved)
+	if err != nil {
+		return &ExtractError{Kind: ViolationUnsafeLink, Entry: name, Detail: fmt.Sprintf("symlink target %q escapes the workspace", linkname)}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	os.Remove(target)
+	return os.Symlink(clean, target)
+}
+
+// hardlink links to a regular file already extracted from the archive
+func (x *extractor) hardlink(name, linkname string) error {
+	if err := x.count(name); err != nil {
+		return err
+	}
+	target, err := x.resolve(name)
+	if err != nil {
+		return err
+	}
+	source, err := x.resolve(linkname)
+	if err != nil {
+		return &ExtractError{Kind: ViolationUnsafeLink, Entry: name, Detail: fmt.Sprintf("hardlink target %q escapes the workspace", linkname)}
+	}
+	info, err := os.Lstat(source)
+	if err != nil || !info.Mode().IsRegular() {
+		return &ExtractError{Kind: ViolationUnsafeLink, Entry: name, Detail: fmt.Sprintf("hardlink target %q is not an extracted file", linkname)}
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	os.Remove(target)
+	return os.Link(source, target)
+}
@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A symlink that climbs back to the workspace root, followed by a link whose
+// target passes through it, stays inside the workspace lexically but not
+// when the kernel resolves it
+func TestSymlinkChainStaysInWorkspace(t *testing.T) {
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deep := strings.Repeat("a/", 10)
+	up := strings.TrimSuffix(strings.Repeat("../", 10), "/")
+	links := []*tar.Header{
+		// deep/up resolves to the workspace root
+		{Typeflag: tar.TypeSymlink, Name: deep + "up", Linkname: up},
+		// Lexically deep/../../secret, through up the filesystem root
+		{Typeflag: tar.TypeSymlink, Name: "x", Linkname: deep + "up/" + up + outside + "/secret"},
+	}
+	src := filepath.Join(t.TempDir(), "links.tar")
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(f)
+	for _, h := range links {
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	dest := t.TempDir()
+	x, err := newExtractor(context.Background(), dest, DefaultExtractLimits, fileSize(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := extractTar(x, src, FormatTar); err != nil {
+		var extractErr *ExtractError
+		if !errors.As(err, &extractErr) {
+			t.Fatalf("extract: %v", err)
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dest, "x")); err == nil && string(data) == "secret" {
+		t.Fatal("symlink chain read a file outside the workspace")
+	}
+	if resolved, err := filepath.EvalSymlinks(filepath.Join(dest, "x")); err == nil && !x.inside(resolved) {
+		t.Fatalf("x resolves to %s, outside the workspace", resolved)
+	}
+}
@@ -0,0 +1,135 @@
+package collector
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Archive formats recognised by DetectFormat
+const (
+	FormatZip    = "zip"
+	FormatTar    = "tar"
+	FormatTarGz  = "tar.gz"
+	FormatTarBz2 = "tar.bz2"
+	FormatTarXz  = "tar.xz"
+	FormatTarZst = "tar.zst"
+)
+
+// ErrUnsupportedFormat is returned for content that is not a known archive.
+var ErrUnsupportedFormat = errors.New("unsupported archive format")
+
+// tarMagicOffset is where the "ustar" magic sits in a tar header
+const tarMagicOffset = 257
+
+var (
+	zipMagic      = []byte("PK\x03\x04")
+	zipEmptyMagic = []byte("PK\x05\x06")
+	gzipMagic     = []byte{0x1f, 0x8b}
+	bzip2Magic    = []byte("BZh")
+	xzMagic       = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	zstdMagic     = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	tarMagic      = []byte("ustar")
+)
+
+// DetectFormat identifies an archive from its leading bytes, ignoring the
+// file name. Compressed streams are assumed to hold a tar.
+func DetectFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, tarMagicOffset+len(tarMagic))
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return "", ErrUnsupportedFormat
+		}
+		return "", err
+	}
+	return detectFormat(head[:n])
+}
+
+func detectFormat(head []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(head, zipMagic), bytes.HasPrefix(head, zipEmptyMagic):
+		return FormatZip, nil
+	case bytes.HasPrefix(head, gzipMagic):
+		return FormatTarGz, nil
+	case bytes.HasPrefix(head, bzip2Magic):
+		return FormatTarBz2, nil
+	case bytes.HasPrefix(head, xzMagic):
+		return FormatTarXz, nil
+	case bytes.HasPrefix(head, zstdMagic):
+		return FormatTarZst, nil
+	case len(head) >= tarMagicOffset+len(tarMagic) &&
+		bytes.Equal(head[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic):
+		return FormatTar, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// formatAliases maps common spellings and file extensions to a format
+var formatAliases = map[string]string{
+	"zip":     FormatZip,
+	"tar":     FormatTar,
+	"tar.gz":  FormatTarGz,
+	"tgz":     FormatTarGz,
+	"tar.bz2": FormatTarBz2,
+	"tbz2":    FormatTarBz2,
+	"tar.xz":  FormatTarXz,
+	"txz":     FormatTarXz,
+	"tar.zst": FormatTarZst,
+	"tzst":    FormatTarZst,
+}
+
+// NormalizeFormat maps a user-supplied format name such as "tgz" or
+// ".tar.xz" to one of the Format constants
+func NormalizeFormat(name string) (string, error) {
+	if format, ok := formatAliases[strings.TrimPrefix(strings.ToLower(name), ".")]; ok {
+		return format, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, name)
+}
+
+// decompress wraps r in the decoder for a tar format. The returned closer
+// releases decoder resources and does not close r.
+func decompress(format string, r io.Reader) (io.Reader, func(), error) {
+	switch format {
+	case FormatTar:
+		return r, func() {}, nil
+	case FormatTarGz:
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gzr, func() { gzr.Close() }, nil
+	case FormatTarBz2:
+		return bzip2.NewReader(r), func() {}, nil
+	case FormatTarXz:
+		xzr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return xzr, func() {}, nil
+	case FormatTarZst:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, zr.Close, nil
+	default:
+		return nil, nil, ErrUnsupportedFormat
+	}
+}
@@ -1,58 +1,507 @@
 package collector
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"log"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/unarya/unarya/internal/shared/utils"
 )
 
-// CollectFromGit clones a repository from a given Git URL and branch.
-// Supports both public and private repositories (via token).
-func CollectFromGit(cfg SourceConfig) (*CollectionResult, error) {
+// commitPattern matches full SHA-1 or SHA-256 commit names. Abbreviated
+// SHAs are refused: a remote can only be asked for a full object name, and
+// resolving an abbreviation would mean fetching every branch first.
+var commitPattern = regexp.MustCompile(`^(?:[0-9a-fA-F]{40}|[0-9a-fA-F]{64})$`)
+
+// CollectFromGit fetches a repository at a branch, tag or commit. Supports
+// both public and private repositories (via token), sparse checkout of a
+// subpath, configurable history depth, and opt-in submodules and LFS.
+func CollectFromGit(ctx context.Context, cfg SourceConfig) (_ *CollectionResult, err error) {
 	if cfg.URL == "" {
-		return nil, fmt.Errorf("git url is empty")
+		return nil, invalidSource(errors.New("git url is empty"))
+	}
+	if err := validateGitRef(cfg); err != nil {
+		return nil, invalidSource(err)
+	}
+	if err := checkSourceHost(ctx, cfg.URL); err != nil {
+		return nil, err
 	}
-	if cfg.LocalPath == "" {
-		cfg.LocalPath = filepath.Join(os.TempDir(), "collector_git_repo")
+	removeKey, err := prepareSSH(&cfg)
+	if err != nil {
+		return nil, err
 	}
+	defer removeKey()
+	cleanup, err := prepareWorkspace(&cfg, "collector-git")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup(&err)
 
-	// If folder exists, remove and reclone
-	os.RemoveAll(cfg.LocalPath)
+	// If folder has content, remove and refetch
+	if entries, _ := os.ReadDir(cfg.LocalPath); len(entries) > 0 {
+		if err := os.RemoveAll(cfg.LocalPath); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(cfg.LocalPath, 0755); err != nil {
+			return nil, err
+		}
+	}
 
-	cloneURL := cfg.URL
-	if cfg.Token != "" {
-		// Inject token for authenticated clone
-		cloneURL = injectToken(cfg.URL, cfg.Token)
+	// init + fetch of a single ref works the same for branches, tags and
+	// commits, and lets sparse checkout be configured before any checkout
+	fetchArgs := []string{"fetch", "--quiet", "--no-tags"}
+	if progressFrom(ctx) != nil {
+		fetchArgs[1] = "--progress"
+	}
+	// A diff against a base needs the history back to their merge base;
+	// mining history needs one more commit than it walks, whose parent
+	// gives the oldest walked commit its diff
+	historyDepth := min(cfg.HistoryDepth, MaxHistoryDepth)
+	depth := gitDepth(cfg.Depth)
+	if depth > 0 && historyDepth > 0 {
+		depth = max(depth, historyDepth+1)
+	}
+	if depth > 0 && cfg.BaseRef == "" {
+		fetchArgs = append(fetchArgs, "--depth", fmt.Sprint(depth))
+	}
+	source, refspec := "origin", gitRefspec(cfg)
+	releaseMirror := func() {}
+	if cache := currentMirrorCache(); cache != nil {
+		m, commit, err := cache.acquire(ctx, cfg)
+		if err != nil {
+			log.Printf("[Collector] Mirror of %s unavailable, fetching directly: %v", utils.RedactURL(cfg.URL), err)
+		} else {
+			releaseMirror = func() { cache.release(m) }
+			source, refspec = m.path, commit
+			fetchArgs = append([]string{"-c", "protocol.file.allow=always"}, fetchArgs...)
+		}
 	}
+	// Fetching from a mirror only moves what the workspace needs across
+	// local disk, so there is no need to filter blobs
+	if cfg.Subpath != "" && source == "origin" {
+		fetchArgs = append(fetchArgs, "--filter=blob:none")
+	}
+	fetchArgs = append(fetchArgs, source, refspec)
 
-	cmdArgs := []string{"clone", "--depth", "1", "-b", cfg.Branch, cloneURL, cfg.LocalPath}
-	if cfg.Branch == "" {
-		cmdArgs = []string{"clone", "--depth", "1", cloneURL, cfg.LocalPath}
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", gitRemote(cfg.URL)},
+	}
+	if cfg.Subpath != "" {
+		steps = append(steps, []string{"sparse-checkout", "set", "--", cfg.Subpath})
 	}
+	steps = append(steps, fetchArgs, []string{"checkout", "--quiet", "--detach", "FETCH_HEAD"})
 
-	cmd := exec.Command("git", cmdArgs...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	for _, args := range steps {
+		if _, err := runGit(ctx, cfg, cfg.LocalPath, args...); err != nil {
+			releaseMirror()
+			return nil, err
+		}
+	}
+	releaseMirror()
 
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("git clone failed: %w", err)
+	if cfg.Submodules {
+		if err := updateSubmodules(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.LFS {
+		if err := fetchLFS(ctx, cfg, cfg.LocalPath); err != nil {
+			return nil, err
+		}
 	}
 
-	// Walk collected files
-	result, err := scanFiles(cfg.LocalPath)
+	commit, err := runGit(ctx, cfg, cfg.LocalPath, "rev-parse", "HEAD")
 	if err != nil {
 		return nil, err
 	}
 
+	root := cfg.LocalPath
+	if cfg.Subpath != "" {
+		root = filepath.Join(cfg.LocalPath, filepath.FromSlash(cfg.Subpath))
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("subpath %q not found at %s", cfg.Subpath, commit)
+		}
+	}
+
+	// Walk collected files
+	result, err := scanFiles(ctx, cfg, root)
+	if err != nil {
+		return nil, err
+	}
+	result.Commit = commit
+	if cfg.BaseRef != "" {
+		if result.Changes, err = diffBase(ctx, cfg, commit); err != nil {
+			return nil, err
+		}
+	}
+	if historyDepth > 0 {
+		if result.History, err = mineHistory(ctx, cfg, result, historyDepth); err != nil {
+			return nil, err
+		}
+	}
+	result.Provenance.Commit = commit
+	if out, err := runGit(ctx, cfg, cfg.LocalPath, "log", "-1", "--format=%an <%ae>%n%cI", "HEAD"); err == nil {
+		author, committed, _ := strings.Cut(out, "\n")
+		result.Provenance.Author = author
+		result.Provenance.CommitTime, _ = time.Parse(time.RFC3339, committed)
+	}
 	return result, nil
 }
 
-// injectToken transforms https://github.com/user/repo.git to include a token.
-func injectToken(url, token string) string {
-	// Example: https://<token>@github.com/user/repo.git
-	if token == "" {
-		return url
+// maxSubmoduleDepth bounds how deeply submodules may nest
+const maxSubmoduleDepth = 8
+
+// updateSubmodules checks out submodules recursively. HTTPS credentials are
+// scoped to the repository's server (see gitEnv), so they reach submodules
+// hosted there and nowhere else; without an SSH key, SSH submodules on that
+// server are fetched over HTTPS so the token applies to them too.
+func updateSubmodules(ctx context.Context, cfg SourceConfig) error {
+	if err := checkoutSubmodules(ctx, cfg, cfg.LocalPath, cfg.Subpath, 0); err != nil {
+		return fmt.Errorf("submodule checkout failed: %w", err)
+	}
+	return nil
+}
+
+// checkoutSubmodules checks out the submodules of the repository in dir,
+// below subpath when set, then theirs. Submodule URLs come from the
+// repository, so each is held to the same host policy as the source before
+// anything is fetched from it.
+func checkoutSubmodules(ctx context.Context, cfg SourceConfig, dir, subpath string, depth int) error {
+	if depth >= maxSubmoduleDepth {
+		return fmt.Errorf("submodules nest more than %d levels deep", maxSubmoduleDepth)
+	}
+	// init copies the URLs to the local config, resolving relative ones
+	initArgs := []string{"submodule", "init"}
+	if subpath != "" {
+		initArgs = append(initArgs, "--", subpath)
+	}
+	if _, err := runGit(ctx, cfg, dir, initArgs...); err != nil {
+		return err
+	}
+	out, err := runGit(ctx, cfg, dir, "config", "--local", "-z", "--list")
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	for _, entry := range strings.Split(out, "\x00") {
+		key, remote, _ := strings.Cut(entry, "\n")
+		name, ok := strings.CutPrefix(key, "submodule.")
+		if !ok || !strings.HasSuffix(name, ".url") {
+			continue
+		}
+		name = strings.TrimSuffix(name, ".url")
+		if err := checkSubmoduleURL(ctx, remote); err != nil {
+			return fmt.Errorf("submodule %s: %w", name, err)
+		}
+		p, err := runGit(ctx, cfg, dir, "config", "-f", ".gitmodules", "--get", "submodule."+name+".path")
+		if err != nil {
+			return fmt.Errorf("submodule %s has no path: %w", name, err)
+		}
+		paths = append(paths, p)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	var args []string
+	if _, ok := gitCredentials(cfg); ok && cfg.sshCommand == "" {
+		if u, err := url.Parse(cfg.URL); err == nil {
+			args = append(args, "-c", "url.https://"+u.Host+"/.insteadOf=git@"+u.Hostname()+":")
+		}
+	}
+	args = append(args, "submodule", "update", "--")
+	if _, err := runGit(ctx, cfg, dir, append(args, paths...)...); err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if err := checkoutSubmodules(ctx, cfg, filepath.Join(dir, filepath.FromSlash(p)), "", depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSubmoduleURL applies the source policy to a submodule URL: HTTPS or
+// SSH only, to an allowed host that is not an internal address
+func checkSubmoduleURL(ctx context.Context, remote string) error {
+	if strings.HasPrefix(remote, "-") ||
+		!(strings.HasPrefix(remote, "https://") || strings.HasPrefix(remote, "ssh://") || scpLikeURL.MatchString(remote)) {
+		return fmt.Errorf("%w: unsupported URL %q", ErrInvalidSource, utils.RedactURL(remote))
+	}
+	host, err := sourceHost(remote)
+	if err != nil {
+		return invalidSource(err)
+	}
+	if !GitHostAllowed(host) {
+		return fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+	}
+	return checkHost(ctx, host)
+}
+
+// validateGitRef checks that at most one ref is requested and that the
+// subpath stays inside the repository
+func validateGitRef(cfg SourceConfig) error {
+	refs := 0
+	for _, ref := range []string{cfg.Branch, cfg.Tag, cfg.Commit} {
+		if ref != "" {
+			refs++
+		}
+	}
+	if refs > 1 {
+		return errors.New("only one of branch, tag and commit may be set")
+	}
+	if cfg.Commit != "" && !commitPattern.MatchString(cfg.Commit) {
+		return fmt.Errorf("invalid commit SHA %q: a full 40 or 64 digit SHA is required", cfg.Commit)
+	}
+	for _, ref := range []string{cfg.Branch, cfg.Tag, cfg.BaseRef} {
+		if strings.HasPrefix(ref, "-") {
+			return fmt.Errorf("invalid ref %q", ref)
+		}
+	}
+	if cfg.Subpath != "" {
+		clean := path.Clean(filepath.ToSlash(cfg.Subpath))
+		if path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") || strings.HasPrefix(clean, "-") {
+			return fmt.Errorf("invalid subpath %q", cfg.Subpath)
+		}
+	}
+	return nil
+}
+
+// gitRefspec names what to fetch for the requested ref
+func gitRefspec(cfg SourceConfig) string {
+	switch {
+	case cfg.Commit != "":
+		return strings.ToLower(cfg.Commit)
+	case cfg.Tag != "":
+		return "refs/tags/" + cfg.Tag
+	case cfg.Branch != "":
+		return "refs/heads/" + cfg.Branch
+	default:
+		return "HEAD"
+	}
+}
+
+// gitDepth maps SourceConfig.Depth to a fetch depth: 0 means a shallow
+// fetch of one commit, a negative value the full history
+func gitDepth(depth int) int {
+	switch {
+	case depth == 0:
+		return 1
+	case depth < 0:
+		return 0
+	default:
+		return depth
+	}
+}
+
+// runGit runs a git command in dir and returns its trimmed stdout, in the
+// environment built by gitEnv. Credentials are redacted from errors. The
+// output of commands run with --progress is reported as StageGit progress
+// when the context asks for it (see WithProgress).
+func runGit(ctx context.Context, cfg SourceConfig, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = gitEnv(cfg)
+	var stderr fmt.Stringer
+	if r := progressFrom(ctx); r != nil && slices.Contains(args, "--progress") {
+		progress := &gitProgress{r: r}
+		defer r.flush()
+		cmd.Stderr, stderr = progress, progress
+	} else {
+		buf := &strings.Builder{}
+		cmd.Stderr, stderr = buf, buf
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := redactGit(cfg, strings.TrimSpace(stderr.String()))
+		return "", fmt.Errorf("git %s failed: %w: %s", gitSubcommand(args), err, msg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitConfig is applied to every git command: hooks never run, credential
+// helpers are not consulted, only HTTPS and SSH transports are allowed and
+// redirects, which could lead past the host checks, are not followed
+var gitConfig = [][2]string{
+	{"core.hooksPath", os.DevNull},
+	{"credential.helper", ""},
+	{"http.followRedirects", "false"},
+	{"protocol.allow", "never"},
+	{"protocol.https.allow", "always"},
+	{"protocol.ssh.allow", "always"},
+}
+
+// gitEnv builds the environment for a git command. Inherited GIT_* variables
+// and the system and global config files are ignored so only gitConfig
+// applies. HTTPS credentials travel as an Authorization header scoped to the
+// repository's server and passed through the environment, never on the
+// command line or in a URL.
+func gitEnv(cfg SourceConfig) []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "GIT_") {
+			env = append(env, kv)
+		}
+	}
+	env = append(env,
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+	)
+	if cfg.sshCommand != "" {
+		env = append(env, "GIT_SSH_COMMAND="+cfg.sshCommand)
+	}
+
+	config := gitConfig
+	if header, ok := gitCredentials(cfg); ok {
+		u, _ := url.Parse(cfg.URL)
+		config = append(config[:len(config):len(config)],
+			[2]string{"http.https://" + u.Host + "/.extraHeader", header})
+	}
+	env = append(env, fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(config)))
+	for i, kv := range config {
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, kv[0]),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, kv[1]),
+		)
+	}
+	return env
+}
+
+// gitCredentials returns the Authorization header for an HTTPS source, from
+// the token or from credentials embedded in the URL
+func gitCredentials(cfg SourceConfig) (string, bool) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", false
+	}
+	var userinfo string
+	switch {
+	case cfg.Token != "":
+		userinfo = cfg.Token + ":"
+	case u.User != nil:
+		password, _ := u.User.Password()
+		userinfo = u.User.Username() + ":" + password
+	default:
+		return "", false
+	}
+	return "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(userinfo)), true
+}
+
+// gitRemote strips credentials from an HTTPS URL; gitEnv supplies them
+func gitRemote(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.User == nil {
+		return raw
+	}
+	u.User = nil
+	return u.String()
+}
+
+// redactGit removes every form of the request's credentials from git output
+func redactGit(cfg SourceConfig, msg string) string {
+	var secrets []string
+	if cfg.Token != "" {
+		secrets = append(secrets, cfg.Token)
+	}
+	if header, ok := gitCredentials(cfg); ok {
+		secrets = append(secrets, strings.TrimPrefix(header, "Authorization: Basic "))
+	}
+	if u, err := url.Parse(cfg.URL); err == nil && u.User != nil {
+		if password, ok := u.User.Password(); ok && password != "" {
+			secrets = append(secrets, password)
+		}
+	}
+	for _, s := range secrets {
+		msg = strings.ReplaceAll(msg, s, "***")
+	}
+	return msg
+}
+
+// gitSubcommand names the command in args for error messages, skipping
+// leading -c options so their values (which may hold credentials) are not
+// echoed
+func gitSubcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
+
+// ResolveRef checks that the repository is reachable with the configured
+// credentials and returns the commit SHA its branch, tag (or HEAD) points
+// to. A requested commit is returned as is. Nothing is cloned.
+func ResolveRef(ctx context.Context, cfg SourceConfig) (string, error) {
+	if cfg.URL == "" {
+		return "", invalidSource(errors.New("git url is empty"))
+	}
+	if err := validateGitRef(cfg); err != nil {
+		return "", invalidSource(err)
+	}
+	if err := checkSourceHost(ctx, cfg.URL); err != nil {
+		return "", err
+	}
+	removeKey, err := prepareSSH(&cfg)
+	if err != nil {
+		return "", err
+	}
+	defer removeKey()
+	return remoteCommit(ctx, cfg)
+}
+
+// remoteCommit asks the remote which commit cfg's ref points to. Remotes do
+// not advertise commits, so a requested commit is only checked for access
+// here and returned as is; fetching it checks that it exists.
+func remoteCommit(ctx context.Context, cfg SourceConfig) (string, error) {
+	ref := gitRefspec(cfg)
+	if cfg.Commit != "" {
+		// Listing the commit would match refs named like it instead
+		if _, err := runGit(ctx, cfg, "", "ls-remote", gitRemote(cfg.URL), "HEAD"); err != nil {
+			return "", err
+		}
+		return ref, nil
+	}
+
+	args := []string{"ls-remote", gitRemote(cfg.URL), ref}
+	if cfg.Tag != "" {
+		args = append(args, ref+"^{}") // commit an annotated tag points to
+	}
+	out, err := runGit(ctx, cfg, "", args...)
+	if err != nil {
+		return "", err
+	}
+
+	// With a tag, prefer the peeled line when there is one
+	var sha string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if sha == "" || strings.HasSuffix(fields[1], "^{}") {
+			sha = fields[0]
+		}
+	}
+	if sha == "" {
+		return "", fmt.Errorf("ref %s not found", ref)
 	}
-	return fmt.Sprintf("https://%s@%s", token, url[8:]) // strip https://
+	return sha, nil
 }
@@ -0,0 +1,134 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxHistoryDepth caps SourceConfig.HistoryDepth
+const MaxHistoryDepth = 10000
+
+// FileHistory summarizes the commits that touched a file. Path is relative
+// to the collection root, like ManifestEntry.Path, so it joins with the
+// manifest and the parser's per-file metrics.
+type FileHistory struct {
+	Path         string
+	Commits      int
+	LinesAdded   int // churn; binary changes count as commits only
+	LinesRemoved int
+	Authors      int // distinct author emails
+	LastModified time.Time
+	LastCommit   string
+}
+
+// History is the result of mining the commits reachable from the head
+type History struct {
+	Commits int // commits walked, merges excluded
+	Files   []FileHistory
+}
+
+// mineHistory walks up to depth non-merge commits from HEAD and summarizes
+// the history of the files in result. Renames are followed, so a file's
+// history includes commits made under its earlier names.
+func mineHistory(ctx context.Context, cfg SourceConfig, result *CollectionResult, depth int) (*History, error) {
+	args := []string{"log", "--no-merges", "-M", "--numstat", "-z", "--no-color",
+		"--format=%x1e%H%x1f%aE%x1f%aI", "-n", strconv.Itoa(depth)}
+	if cfg.Subpath != "" {
+		args = append(args, "--relative="+cfg.Subpath)
+	}
+	args = append(args, "HEAD")
+	if cfg.Subpath != "" {
+		args = append(args, "--", cfg.Subpath)
+	}
+	out, err := runGit(ctx, cfg, cfg.LocalPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	stats := map[string]*FileHistory{}
+	authors := map[string]map[string]bool{}
+	alias := map[string]string{} // earlier name -> name at the head
+	current := func(p string) string {
+		for i := 0; i < 100; i++ {
+			next, ok := alias[p]
+			if !ok {
+				break
+			}
+			p = next
+		}
+		return p
+	}
+	touch := func(p, commit, author string, when time.Time, added, removed string) {
+		fh := stats[p]
+		if fh == nil {
+			// Commits come newest first, so the first one seen is the last change
+			fh = &FileHistory{Path: p, LastModified: when, LastCommit: commit}
+			stats[p] = fh
+			authors[p] = map[string]bool{}
+		}
+		fh.Commits++
+		if n, err := strconv.Atoi(added); err == nil {
+			fh.LinesAdded += n
+		}
+		if n, err := strconv.Atoi(removed); err == nil {
+			fh.LinesRemoved += n
+		}
+		authors[p][strings.ToLower(author)] = true
+	}
+
+	history := &History{}
+	for _, record := range strings.Split(out, "\x1e") {
+		header, body, ok := strings.Cut(record, "\x00")
+		if !ok {
+			continue
+		}
+		meta := strings.Split(header, "\x1f")
+		if len(meta) != 3 {
+			continue
+		}
+		commit, author := meta[0], meta[1]
+		when, _ := time.Parse(time.RFC3339, meta[2])
+		history.Commits++
+
+		fields := strings.Split(strings.TrimPrefix(body, "\n"), "\x00")
+		for i := 0; i < len(fields); i++ {
+			parts := strings.SplitN(fields[i], "\t", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			p := parts[2]
+			if p == "" {
+				// A rename: the old and new names follow
+				if i+2 >= len(fields) {
+					break
+				}
+				oldPath, newPath := fields[i+1], fields[i+2]
+				i += 2
+				p = current(newPath)
+				if oldPath != p {
+					alias[oldPath] = p
+				}
+			} else {
+				p = current(p)
+			}
+			touch(p, commit, author, when, parts[0], parts[1])
+		}
+	}
+
+	present := map[string]bool{}
+	for _, f := range result.Files {
+		present[relSlash(result.Root, f.Path)] = true
+	}
+	for p, fh := range stats {
+		if present[p] {
+			fh.Authors = len(authors[p])
+			history.Files = append(history.Files, *fh)
+		}
+	}
+	sort.Slice(history.Files, func(i, j int) bool { return history.Files[i].Path < history.Files[j].Path })
+	return history, nil
+}
@@ -1,43 +1,45 @@
 package collector
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 )
 
-// CollectFromURL downloads a single file or directory structure via HTTP(S).
-func CollectFromURL(cfg SourceConfig) (*CollectionResult, error) {
+// CollectFromURL downloads a single file via HTTP(S) into the workspace,
+// named after its Content-Disposition or URL.
+func CollectFromURL(ctx context.Context, cfg SourceConfig) (_ *CollectionResult, err error) {
 	if cfg.URL == "" {
 		return nil, fmt.Errorf("url is empty")
 	}
-	if cfg.LocalPath == "" {
-		cfg.LocalPath = filepath.Join(os.TempDir(), "collector_http")
-	}
-	if err := os.MkdirAll(cfg.LocalPath, os.ModePerm); err != nil {
+	cleanup, err := prepareWorkspace(&cfg, "collector-url")
+	if err != nil {
 		return nil, err
 	}
+	defer cleanup(&err)
 
-	fileName := filepath.Base(cfg.URL)
-	targetPath := filepath.Join(cfg.LocalPath, fileName)
-
-	resp, err := http.Get(cfg.URL)
+	dl, err := DefaultDownloader.Fetch(ctx, cfg.URL, cfg.LocalPath, cfg.SHA256)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch file: %w", err)
 	}
-	defer resp.Body.Close()
-
-	out, err := os.Create(targetPath)
-	if err != nil {
+	if err := os.Rename(dl.Path, filepath.Join(cfg.LocalPath, dl.Name)); err != nil {
+		os.Remove(dl.Path)
 		return nil, err
 	}
-	defer out.Close()
 
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		return nil, err
-	}
+	return scanFiles(ctx, cfg, cfg.LocalPath)
+}
 
-	return scanFiles(cfg.LocalPath)
+// fileNameFromURL picks a safe local file name for a downloaded URL
+func fileNameFromURL(rawURL string) string {
+	name := "download"
+	if u, err := url.Parse(rawURL); err == nil {
+		if base := path.Base(u.Path); base != "/" && base != "." && base != ".." {
+			name = base
+		}
+	}
+	return name
 }
@@ -0,0 +1,183 @@
+package collector
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is read, like .gitignore, in every collected directory.
+// Its rules take precedence over .gitignore and the default excludes.
+const IgnoreFileName = ".unaryaignore"
+
+// DefaultExcludes are left out of every collection unless an ignore file
+// re-includes them with a "!" rule. Build outputs are only excluded at the
+// root, where build tools put them, since names like build/ and out/ are
+// also used for source packages deeper down. Vendored trees are kept and
+// classified as vendored.
+var DefaultExcludes = []string{
+	".git/", ".hg/", ".svn/",
+	"__pycache__/", ".venv/", "venv/", ".tox/", ".mypy_cache/", ".pytest_cache/",
+	"/dist/", "/build/", "/target/", "/out/", ".next/", ".gradle/",
+	"*.pyc", "*.pyo", "*.class", "*.o", "*.obj", "*.a", "*.so", "*.dll", "*.dylib", "*.exe",
+	".DS_Store", "Thumbs.db",
+}
+
+// ignoreRule is one line of a gitignore-style file
+type ignoreRule struct {
+	re      *regexp.Regexp // matches a path relative to the file's directory
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreMatcher applies the default excludes and the .gitignore and
+// .unaryaignore files found while walking a tree. Rules from deeper
+// directories and later files win, as in git.
+type ignoreMatcher struct {
+	rules map[string][]ignoreRule // by slash-separated directory, "" for the root
+}
+
+func newIgnoreMatcher() *ignoreMatcher {
+	return &ignoreMatcher{rules: map[string][]ignoreRule{"": parseIgnoreRules(DefaultExcludes)}}
+}
+
+// load reads the ignore files of a directory, given relative to the root
+func (m *ignoreMatcher) load(root, dir string) {
+	for _, name := range []string{".gitignore", IgnoreFileName} {
+		lines, err := readLines(filepath.Join(root, filepath.FromSlash(dir), name))
+		if err != nil {
+			continue
+		}
+		m.rules[dir] = append(m.rules[dir], parseIgnoreRules(lines)...)
+	}
+}
+
+// Ignored reports whether a slash-separated path relative to the root is
+// excluded. Callers skip ignored directories, so a file inside one cannot be
+// re-included, again as in git.
+func (m *ignoreMatcher) Ignored(rel string, isDir bool) bool {
+	ignored := false
+	parts := strings.Split(rel, "/")
+	// Rules of the root first, then of each directory down to the parent
+	for depth := 0; depth < len(parts); depth++ {
+		dir := strings.Join(parts[:depth], "/")
+		sub := strings.Join(parts[depth:], "/")
+		for _, r := range m.rules[dir] {
+			if r.dirOnly && !isDir {
+				continue
+			}
+			if r.re.MatchString(sub) {
+				ignored = !r.negate
+			}
+		}
+	}
+	return ignored
+}
+
+func readLines(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	return lines, sc.Err()
+}
+
+// parseIgnoreRules compiles gitignore syntax: comments, "!" negation, a
+// trailing "/" for directories only, anchoring by a leading or inner "/",
+// and the *, ?, [...] and ** wildcards
+func parseIgnoreRules(lines []string) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var r ignoreRule
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		expr := globToRegexp(line)
+		if !anchored {
+			expr = "(?:.*/)?" + expr
+		}
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			continue
+		}
+		r.re = re
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// globToRegexp translates one gitignore pattern to a regular expression
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// relSlash returns path relative to root with forward slashes
+func relSlash(root, p string) string {
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		return p
+	}
+	return path.Clean(filepath.ToSlash(rel))
+}
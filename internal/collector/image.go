@@ -0,0 +1,459 @@
+package collector
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidImage is returned for tarballs that are neither a docker save
+// archive nor an OCI image layout.
+var ErrInvalidImage = errors.New("not a docker save or OCI layout tarball")
+
+// DefaultImageLimits bound both the image tarball and the flattened root
+// filesystem, which are larger than typical source archives
+var DefaultImageLimits = ExtractLimits{
+	MaxEntries:    500000,
+	MaxTotalBytes: 8 << 30,
+	MaxFileBytes:  4 << 30,
+	MaxRatio:      100,
+}
+
+// Whiteout markers of the OCI layer format
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// ImageInfo describes a collected container image
+type ImageInfo struct {
+	RepoTags     []string
+	OS           string
+	Architecture string
+	Entrypoint   []string
+	Cmd          []string
+	Env          []string
+	ExposedPorts []string // e.g. "8080/tcp"
+	User         string   // empty means root
+	WorkingDir   string
+	Labels       map[string]string
+	Layers       int
+	Packages     []OSPackage
+	Warnings     []string // package databases that could not be read
+}
+
+// imageConfig is the part of an image configuration blob we read
+type imageConfig struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Config       struct {
+		User         string              `json:"User"`
+		Env          []string            `json:"Env"`
+		Entrypoint   []string            `json:"Entrypoint"`
+		Cmd          []string            `json:"Cmd"`
+		WorkingDir   string              `json:"WorkingDir"`
+		ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+		Labels       map[string]string   `json:"Labels"`
+	} `json:"config"`
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+// imageLayout locates the configuration and layers of an image unpacked
+// from its tarball, as paths below the unpacked directory
+type imageLayout struct {
+	config   string
+	layers   []string
+	repoTags []string
+}
+
+// CollectFromImage downloads a docker save or OCI layout tarball and
+// flattens its layers into the workspace
+func CollectFromImage(ctx context.Context, cfg SourceConfig) (_ *CollectionResult, err error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("image url is empty")
+	}
+	dl, err := DefaultDownloader.Fetch(ctx, cfg.URL, "", cfg.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer os.Remove(dl.Path)
+	return CollectFromImageFile(ctx, cfg, dl.Path)
+}
+
+// CollectFromImageFile flattens the layers of an image tarball on disk into
+// the workspace, applying whiteouts, and reads the image configuration and
+// the OS package databases of the result
+func CollectFromImageFile(ctx context.Context, cfg SourceConfig, tarball string) (_ *CollectionResult, err error) {
+	cleanup, err := prepareWorkspace(&cfg, "collector-image")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup(&err)
+
+	// The tarball is unpacked outside the workspace so its blobs are not
+	// collected themselves
+	blobs, err := os.MkdirTemp("", "collector-image-blobs-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(blobs)
+
+	format, err := DetectFormat(tarball)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatZip {
+		return nil, ErrInvalidImage
+	}
+	bx, err := newExtractor(ctx, blobs, DefaultImageLimits, fileSize(tarball))
+	if err != nil {
+		return nil, err
+	}
+	if err := extractTar(bx, tarball, format); err != nil {
+		return nil, err
+	}
+
+	layout, err := readImageLayout(blobs)
+	if err != nil {
+		return nil, err
+	}
+	var config imageConfig
+	if err := readJSONFile(layout.config, &config); err != nil {
+		return nil, fmt.Errorf("invalid image config: %w", err)
+	}
+	if len(config.RootFS.DiffIDs) != len(layout.layers) {
+		return nil, fmt.Errorf("image config lists %d layers, manifest %d", len(config.RootFS.DiffIDs), len(layout.layers))
+	}
+
+	// Layers are compressed blobs, so the ratio applies to each of them
+	// rather than to the rootfs as a whole
+	limits := DefaultImageLimits
+	limits.MaxRatio = 0
+	rx, err := newExtractor(ctx, cfg.LocalPath, limits, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i, layer := range layout.layers {
+		rx.step = fmt.Sprintf("layer %d/%d", i+1, len(layout.layers))
+		if err := applyLayer(rx, layer, config.RootFS.DiffIDs[i]); err != nil {
+			return nil, fmt.Errorf("layer %d: %w", i+1, err)
+		}
+	}
+
+	info := &ImageInfo{
+		RepoTags:     layout.repoTags,
+		OS:           config.OS,
+		Architecture: config.Architecture,
+		Entrypoint:   config.Config.Entrypoint,
+		Cmd:          config.Config.Cmd,
+		Env:          config.Config.Env,
+		User:         config.Config.User,
+		WorkingDir:   config.Config.WorkingDir,
+		Labels:       config.Config.Labels,
+		Layers:       len(layout.layers),
+	}
+	for port := range config.Config.ExposedPorts {
+		info.ExposedPorts = append(info.ExposedPorts, port)
+	}
+	sort.Strings(info.ExposedPorts)
+	info.Packages, info.Warnings = inventoryPackages(ctx, cfg.LocalPath)
+
+	result, err := scanFiles(ctx, cfg, cfg.LocalPath)
+	if err != nil {
+		return nil, err
+	}
+	result.Image = info
+	return result, nil
+}
+
+// readImageLayout finds the config and layers of the first image in an
+// unpacked tarball, from docker's manifest.json or else the OCI index.json.
+// For a multi-platform index, linux/amd64 is preferred.
+func readImageLayout(dir string) (*imageLayout, error) {
+	var manifest []struct {
+		Config   string
+		RepoTags []string
+		Layers   []string
+	}
+	if err := readJSONFile(filepath.Join(dir, "manifest.json"), &manifest); err == nil && len(manifest) > 0 {
+		m := manifest[0]
+		layout := &imageLayout{repoTags: m.RepoTags}
+		if layout.config, err = imagePath(dir, m.Config); err != nil {
+			return nil, err
+		}
+		for _, l := range m.Layers {
+			p, err := imagePath(dir, l)
+			if err != nil {
+				return nil, err
+			}
+			layout.layers = append(layout.layers, p)
+		}
+		return layout, nil
+	}
+
+	type descriptor struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+		Platform    *struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+		} `json:"platform"`
+	}
+	var index struct {
+		Manifests []descriptor `json:"manifests"`
+	}
+	if err := readJSONFile(filepath.Join(dir, "index.json"), &index); err != nil {
+		return nil, ErrInvalidImage
+	}
+	var tags []string
+	for depth := 0; depth < 4; depth++ {
+		if len(index.Manifests) == 0 {
+			return nil, fmt.Errorf("%w: empty image index", ErrInvalidImage)
+		}
+		chosen := index.Manifests[0]
+		for _, m := range index.Manifests {
+			if m.Platform != nil && m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+				chosen = m
+				break
+			}
+		}
+		if tag := chosen.Annotations["org.opencontainers.image.ref.name"]; tag != "" {
+			tags = append(tags, tag)
+		}
+		blob, err := blobPath(dir, chosen.Digest)
+		if err != nil {
+			return nil, err
+		}
+		var m struct {
+			Manifests []descriptor `json:"manifests"`
+			Config    descriptor   `json:"config"`
+			Layers    []descriptor `json:"layers"`
+		}
+		if err := readJSONFile(blob, &m); err != nil {
+			return nil, fmt.Errorf("invalid image manifest: %w", err)
+		}
+		if len(m.Manifests) > 0 {
+			// A nested index, as written for multi-platform images
+			index.Manifests = m.Manifests
+			continue
+		}
+		layout := &imageLayout{repoTags: tags}
+		if layout.config, err = blobPath(dir, m.Config.Digest); err != nil {
+			return nil, err
+		}
+		for _, l := range m.Layers {
+			p, err := blobPath(dir, l.Digest)
+			if err != nil {
+				return nil, err
+			}
+			layout.layers = append(layout.layers, p)
+		}
+		return layout, nil
+	}
+	return nil, fmt.Errorf("%w: image indexes nested too deeply", ErrInvalidImage)
+}
+
+// imagePath resolves a path named by manifest.json inside the tarball
+func imagePath(dir, name string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("%w: unsafe path %q", ErrInvalidImage, name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(name)), nil
+}
+
+// blobPath resolves an OCI blob and checks it against its digest
+func blobPath(dir, digest string) (string, error) {
+	hexDigest, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok || len(hexDigest) != 64 || strings.Trim(hexDigest, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("%w: unsupported digest %q", ErrInvalidImage, digest)
+	}
+	p := filepath.Join(dir, "blobs", "sha256", hexDigest)
+	f, err := os.Open(p)
+	if err != nil {
+		return "", fmt.Errorf("%w: missing blob %s", ErrInvalidImage, digest)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != hexDigest {
+		return "", fmt.Errorf("%w: blob %s", ErrChecksumMismatch, digest)
+	}
+	return p, nil
+}
+
+func readJSONFile(name string, v any) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// applyLayer extracts one layer over the root filesystem built so far. The
+// uncompressed stream must match diffID from the image config.
+func applyLayer(x *extractor, layer, diffID string) error {
+	format, err := DetectFormat(layer)
+	if err != nil {
+		return err
+	}
+	if format == FormatZip {
+		return fmt.Errorf("%w: zip layer", ErrInvalidImage)
+	}
+	f, err := os.Open(layer)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, closeDecoder, err := decompress(format, f)
+	if err != nil {
+		return err
+	}
+	defer closeDecoder()
+
+	h := sha256.New()
+	tr := tar.NewReader(io.TeeReader(r, h))
+	written := map[string]bool{} // paths this layer added, which opaque markers keep
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// Names are relative to the image root, which ".." cannot leave
+		name := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		if name == "" {
+			continue
+		}
+		err = applyLayerEntry(x, tr, hdr, name, written)
+		var extractErr *ExtractError
+		if errors.As(err, &extractErr) && extractErr.Kind == ViolationUnsafeLink {
+			// Real layers never need this; skip rather than fail the image
+			log.Printf("[Collector] Skipping image entry %s: %v", hdr.Name, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Hash the tar trailer too
+	if _, err := io.Copy(io.Discard, io.TeeReader(r, h)); err != nil {
+		return err
+	}
+	if got := "sha256:" + hex.EncodeToString(h.Sum(nil)); got != diffID {
+		return fmt.Errorf("%w: diff_id %s, layer is %s", ErrChecksumMismatch, diffID, got)
+	}
+	return nil
+}
+
+func applyLayerEntry(x *extractor, tr *tar.Reader, hdr *tar.Header, name string, written map[string]bool) error {
+	dir, base := path.Split(name)
+	switch {
+	case base == whiteoutOpaque:
+		return opaqueDir(x, strings.TrimSuffix(dir, "/"), written)
+	case strings.HasPrefix(base, whiteoutPrefix):
+		target, err := x.resolve(dir + strings.TrimPrefix(base, whiteoutPrefix))
+		if err != nil {
+			return err
+		}
+		return os.RemoveAll(target)
+	}
+
+	target, err := x.resolve(name)
+	if err != nil {
+		return err
+	}
+	// An entry replaces whatever lower layers left at its path, except that
+	// directories merge
+	if info, err := os.Lstat(target); err == nil && !(info.IsDir() && hdr.Typeflag == tar.TypeDir) {
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+	}
+	written[name] = true
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return x.dir(name)
+	case tar.TypeReg:
+		return x.file(name, tr, hdr.FileInfo().Mode(), 0)
+	case tar.TypeSymlink:
+		return x.symlink(name, rootedLink(name, hdr.Linkname))
+	case tar.TypeLink:
+		return x.hardlink(name, strings.TrimPrefix(path.Clean("/"+hdr.Linkname), "/"))
+	default:
+		// devices, pipes and metadata entries are skipped
+		return nil
+	}
+}
+
+// rootedLink rewrites a symlink target as the relative link it is inside
+// the image: absolute targets start at the image root, and ".." stops there
+func rootedLink(name, linkname string) string {
+	target := linkname
+	if !path.IsAbs(target) {
+		target = path.Join("/", path.Dir(name), target)
+	}
+	target = path.Clean("/" + target)
+	rel, err := filepath.Rel(path.Join("/", path.Dir(name)), target)
+	if err != nil {
+		return "."
+	}
+	return filepath.ToSlash(rel)
+}
+
+// opaqueDir empties a directory of everything lower layers put there
+func opaqueDir(x *extractor, dir string, written map[string]bool) error {
+	target, err := x.resolve(dir)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(target)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := path.Join(dir, e.Name())
+		if keepOpaque(name, written) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(target, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keepOpaque reports whether the current layer wrote name or something
+// below it
+func keepOpaque(name string, written map[string]bool) bool {
+	if written[name] {
+		return true
+	}
+	for w := range written {
+		if strings.HasPrefix(w, name+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+
+	"github.com/unarya/unarya/internal/shared/utils"
+)
+
+// lfsPointerMaxSize is the largest file checked for the LFS pointer format;
+// real pointers are around 130 bytes
+const lfsPointerMaxSize = 1024
+
+var lfsPointerPrefix = []byte("version https://git-lfs.github.com/spec/v1\n")
+
+// isLFSPointer reports whether a file is a Git LFS pointer rather than the
+// content it stands for
+func isLFSPointer(path string, size int64) bool {
+	if size > lfsPointerMaxSize || size < int64(len(lfsPointerPrefix)) {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, lfsPointerMaxSize))
+	if err != nil {
+		return false
+	}
+	return bytes.HasPrefix(data, lfsPointerPrefix) && bytes.Contains(data, []byte("\noid sha256:"))
+}
+
+// LFSAvailable reports whether the git-lfs extension is installed
+func LFSAvailable() bool {
+	return exec.Command("git", "lfs", "version").Run() == nil
+}
+
+// fetchLFS replaces LFS pointers in a checkout with their content. When
+// git-lfs is not installed the pointers are left in place and marked in
+// the scan instead.
+func fetchLFS(ctx context.Context, cfg SourceConfig, dir string) error {
+	if !LFSAvailable() {
+		log.Printf("[Collector] git-lfs is not installed; LFS files in %s stay as pointers", utils.RedactURL(cfg.URL))
+		return nil
+	}
+	if _, err := runGit(ctx, cfg, dir, "lfs", "install", "--local", "--skip-repo"); err != nil {
+		return err
+	}
+	args := []string{"lfs", "pull"}
+	if cfg.Subpath != "" {
+		args = append(args, "--include", cfg.Subpath+"/**")
+	}
+	if _, err := runGit(ctx, cfg, dir, args...); err != nil {
+		return fmt.Errorf("failed to fetch LFS objects: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/unarya/unarya/internal/shared/utils"
+)
+
+// ErrLocalPathNotAllowed is returned for local sources outside the allowed roots.
+var ErrLocalPathNotAllowed = errors.New("local path is outside the allowed roots")
+
+var (
+	localRootsMu sync.RWMutex
+	localRoots   []string
+)
+
+// AllowLocalRoots registers directories that local sources may be read
+// from. With no roots registered, local sources are rejected.
+func AllowLocalRoots(roots ...string) {
+	localRootsMu.Lock()
+	defer localRootsMu.Unlock()
+	for _, root := range roots {
+		if root = strings.TrimSpace(root); root == "" {
+			continue
+		}
+		resolved, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			log.Printf("[Collector] Ignoring local root %s: %v", root, err)
+			continue
+		}
+		abs, err := filepath.Abs(resolved)
+		if err != nil {
+			continue
+		}
+		localRoots = append(localRoots, abs)
+	}
+}
+
+// LocalPath converts a local source (a path or file:// URL) to a cleaned
+// absolute path without touching the filesystem.
+func LocalPath(source string) (string, error) {
+	p := source
+	if strings.HasPrefix(source, "file://") {
+		u, err := url.Parse(source)
+		if err != nil {
+			return "", fmt.Errorf("invalid file URL: %w", err)
+		}
+		if u.Host != "" && u.Host != "localhost" {
+			return "", fmt.Errorf("file URL must not name a remote host")
+		}
+		p = u.Path
+	}
+	if !filepath.IsAbs(p) {
+		return "", fmt.Errorf("local path must be absolute: %q", source)
+	}
+	return filepath.Clean(p), nil
+}
+
+// CheckLocalPath resolves a local source, following symlinks, and verifies
+// it is a directory below one of the allowed roots.
+func CheckLocalPath(source string) (string, error) {
+	p, err := LocalPath(source)
+	if err != nil {
+		return "", invalidSource(err)
+	}
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return "", fmt.Errorf("cannot access local path: %w", err)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("local path must be a directory: %s", p)
+	}
+
+	localRootsMu.RLock()
+	defer localRootsMu.RUnlock()
+	for _, root := range localRoots {
+		if rel, err := filepath.Rel(root, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", ErrLocalPathNotAllowed
+}
+
+// CollectFromLocal collects a directory already on disk. With Snapshot set
+// the directory is copied into the workspace so later stages never see
+// changes made to the original; otherwise the result points at the original.
+func CollectFromLocal(ctx context.Context, cfg SourceConfig) (_ *CollectionResult, err error) {
+	src, err := CheckLocalPath(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Snapshot {
+		return scanFiles(ctx, cfg, src)
+	}
+
+	cleanup, err := prepareWorkspace(&cfg, "collector-local")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup(&err)
+
+	if err := snapshotDir(ctx, src, cfg.LocalPath); err != nil {
+		return nil, fmt.Errorf("failed to snapshot %s: %w", src, err)
+	}
+	return scanFiles(ctx, cfg, cfg.LocalPath)
+}
+
+// snapshotDir copies the regular files and directories of src into dst.
+// Symlinks are not followed, so the copy cannot reach outside src.
+func snapshotDir(ctx context.Context, src, dst string) error {
+	skipped := 0
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case d.IsDir():
+			return utils.EnsureDir(target)
+		case d.Type().IsRegular():
+			return utils.CopyFile(path, target)
+		default:
+			skipped++
+			return nil
+		}
+	})
+	if skipped > 0 {
+		log.Printf("[Collector] Snapshot of %s skipped %d symlinks or special files", src, skipped)
+	}
+	return err
+}
+
+// localSize totals the regular files below a local source
+func localSize(source string) (int64, error) {
+	src, err := CheckLocalPath(source)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
@@ -0,0 +1,103 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/unarya/unarya/internal/shared/utils"
+)
+
+// Provenance records where a collection came from
+type Provenance struct {
+	SourceType string
+	URL        string // remote URL without credentials; empty for local sources
+	Commit     string // git only
+	Author     string // git only, "Name <email>" of the commit author
+	CommitTime time.Time
+	FetchedAt  time.Time
+}
+
+// ManifestEntry describes one collected file by its slash-separated path
+// relative to the collection root
+type ManifestEntry struct {
+	Path     string
+	Size     int64
+	SHA256   string
+	Class    string
+	Language string
+}
+
+// newProvenance starts the provenance of a collection from its request
+func newProvenance(cfg SourceConfig) Provenance {
+	p := Provenance{SourceType: cfg.Type, FetchedAt: time.Now().UTC()}
+	if cfg.Type != "local" {
+		p.URL = utils.StripCredentials(cfg.URL)
+	}
+	return p
+}
+
+// Manifest lists the collected files sorted by path
+func Manifest(result *CollectionResult) []ManifestEntry {
+	entries := make([]ManifestEntry, 0, len(result.Files))
+	for _, f := range result.Files {
+		entries = append(entries, ManifestEntry{
+			Path:     relSlash(result.Root, f.Path),
+			Size:     f.Size,
+			SHA256:   f.SHA256,
+			Class:    f.Class,
+			Language: f.Language,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries
+}
+
+// MerkleRoot computes a Merkle tree hash, in the layout of RFC 6962, over
+// entries sorted by path. Each leaf covers a file's path, size and SHA-256,
+// so the root changes when any file is added, removed, renamed or edited.
+// The result reads "sha256:<hex>".
+func MerkleRoot(entries []ManifestEntry) string {
+	leaves := make([][]byte, len(entries))
+	for i, e := range entries {
+		leaves[i] = manifestLeaf(e)
+	}
+	return "sha256:" + hex.EncodeToString(merkleHash(leaves))
+}
+
+// manifestLeaf hashes one entry, prefixed with 0x00 so leaves cannot be
+// mistaken for inner nodes
+func manifestLeaf(e ManifestEntry) []byte {
+	h := sha256.New()
+	h.Write([]byte{0})
+	h.Write([]byte(filepath.ToSlash(e.Path)))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(e.Size, 10)))
+	h.Write([]byte{0})
+	h.Write([]byte(e.SHA256))
+	return h.Sum(nil)
+}
+
+// merkleHash splits leaves at the largest power of two below their count
+// and hashes both halves under a 0x01 prefix
+func merkleHash(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	case 1:
+		return leaves[0]
+	}
+	k := 1
+	for k*2 < len(leaves) {
+		k *= 2
+	}
+	h := sha256.New()
+	h.Write([]byte{1})
+	h.Write(merkleHash(leaves[:k]))
+	h.Write(merkleHash(leaves[k:]))
+	return h.Sum(nil)
+}
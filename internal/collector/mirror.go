@@ -0,0 +1,264 @@
+package collector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unarya/unarya/internal/shared/utils"
+)
+
+// mirrorSuffix ends the directory name of every mirror
+const mirrorSuffix = ".git"
+
+// MirrorCache keeps bare mirrors of the git repositories that are collected
+// often, so a collection fetches only what changed since the last one and
+// checks the workspace out from local disk. Mirrors are keyed by normalized
+// remote URL. Once they exceed the quota, the least recently used ones not
+// in use are evicted.
+type MirrorCache struct {
+	mu      sync.Mutex
+	base    string
+	quota   int64 // bytes; 0 disables eviction
+	mirrors map[string]*gitMirror
+}
+
+// gitMirror is one bare mirror. Updates hold lock for writing and readers
+// hold it for reading, so requests for the same repository wait for a
+// running fetch and then find what it fetched.
+type gitMirror struct {
+	key      string
+	path     string
+	lock     sync.RWMutex
+	users    int // guarded by MirrorCache.mu, like size and lastUsed
+	size     int64
+	lastUsed time.Time
+}
+
+var (
+	mirrorCacheMu sync.RWMutex
+	mirrorCache   *MirrorCache
+)
+
+// SetMirrorCache makes git collections go through c; nil disables mirrors
+func SetMirrorCache(c *MirrorCache) {
+	mirrorCacheMu.Lock()
+	defer mirrorCacheMu.Unlock()
+	mirrorCache = c
+}
+
+func currentMirrorCache() *MirrorCache {
+	mirrorCacheMu.RLock()
+	defer mirrorCacheMu.RUnlock()
+	return mirrorCache
+}
+
+// NewMirrorCache creates base if needed and adopts the mirrors left by a
+// previous process
+func NewMirrorCache(base string, quota int64) (*MirrorCache, error) {
+	if err := os.MkdirAll(base, 0755); err != nil {
+		return nil, err
+	}
+	c := &MirrorCache{base: base, quota: quota, mirrors: make(map[string]*gitMirror)}
+
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		key, ok := strings.CutSuffix(e.Name(), mirrorSuffix)
+		if !e.IsDir() || !ok {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(base, e.Name())
+		c.mirrors[key] = &gitMirror{key: key, path: path, size: dirSize(path), lastUsed: info.ModTime()}
+	}
+	if len(c.mirrors) > 0 {
+		log.Printf("[Collector] Adopted %d git mirrors from %s", len(c.mirrors), base)
+	}
+	return c, nil
+}
+
+// acquire brings the mirror of cfg's repository up to date for the
+// requested ref and returns it with the commit to check out. The ref is
+// always resolved against the remote with the request's own credentials,
+// so a mirror never serves a request the remote would refuse. The mirror
+// stays readable until it is released.
+func (c *MirrorCache) acquire(ctx context.Context, cfg SourceConfig) (*gitMirror, string, error) {
+	key := mirrorKey(cfg.URL)
+	c.mu.Lock()
+	m, ok := c.mirrors[key]
+	if !ok {
+		m = &gitMirror{key: key, path: filepath.Join(c.base, key+mirrorSuffix)}
+		c.mirrors[key] = m
+	}
+	m.users++
+	m.lastUsed = time.Now()
+	c.mu.Unlock()
+
+	commit, err := c.update(ctx, cfg, m)
+	if err != nil {
+		c.done(m)
+		return nil, "", err
+	}
+	m.lock.RLock()
+	return m, commit, nil
+}
+
+// update resolves the requested ref and fetches into the mirror unless it
+// already holds the commit
+func (c *MirrorCache) update(ctx context.Context, cfg SourceConfig, m *gitMirror) (string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, err := os.Stat(filepath.Join(m.path, "HEAD")); err != nil {
+		if err := initMirror(ctx, cfg, m.path); err != nil {
+			os.RemoveAll(m.path)
+			return "", fmt.Errorf("failed to create mirror: %w", err)
+		}
+	}
+	commit, err := remoteCommit(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+	if !mirrorHas(ctx, cfg, m.path, commit) {
+		if err := fetchMirror(ctx, cfg, m.path, commit); err != nil {
+			return "", err
+		}
+		size := dirSize(m.path)
+		c.mu.Lock()
+		m.size = size
+		c.mu.Unlock()
+		log.Printf("[Collector] Updated mirror of %s (%d bytes)", utils.RedactURL(cfg.URL), size)
+	}
+	return commit, nil
+}
+
+// fetchMirror updates all branches and tags of a mirror, then fetches
+// commit on its own if none of them reaches it
+func fetchMirror(ctx context.Context, cfg SourceConfig, dir, commit string) error {
+	progress := "--quiet"
+	if progressFrom(ctx) != nil {
+		progress = "--progress"
+	}
+	if _, err := runGit(ctx, cfg, dir, "fetch", progress, "--prune", "origin"); err != nil {
+		return err
+	}
+	if mirrorHas(ctx, cfg, dir, commit) {
+		return nil
+	}
+	// A commit such as a pull request head; the ref keeps it from being
+	// garbage collected
+	_, err := runGit(ctx, cfg, dir, "fetch", progress, "origin", commit+":refs/pinned/"+commit)
+	return err
+}
+
+// release ends a use of a mirror returned by acquire
+func (c *MirrorCache) release(m *gitMirror) {
+	m.lock.RUnlock()
+	c.done(m)
+}
+
+// done drops a user of m and evicts mirrors over the quota
+func (c *MirrorCache) done(m *gitMirror) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m.users--
+	m.lastUsed = time.Now()
+	os.Chtimes(m.path, m.lastUsed, m.lastUsed)
+	c.makeRoom()
+}
+
+// makeRoom evicts idle mirrors, least recently used first, until the
+// mirrors fit the quota
+func (c *MirrorCache) makeRoom() {
+	if c.quota <= 0 || c.used() <= c.quota {
+		return
+	}
+	var idle []*gitMirror
+	for _, m := range c.mirrors {
+		if m.users == 0 {
+			idle = append(idle, m)
+		}
+	}
+	sort.Slice(idle, func(i, j int) bool { return idle[i].lastUsed.Before(idle[j].lastUsed) })
+	for _, m := range idle {
+		if c.used() <= c.quota {
+			break
+		}
+		if err := os.RemoveAll(m.path); err != nil {
+			log.Printf("[Collector] Failed to evict mirror %s: %v", m.key, err)
+			continue
+		}
+		delete(c.mirrors, m.key)
+		log.Printf("[Collector] Evicted mirror %s to stay within the mirror quota", m.key)
+	}
+}
+
+func (c *MirrorCache) used() int64 {
+	var total int64
+	for _, m := range c.mirrors {
+		total += m.size
+	}
+	return total
+}
+
+// initMirror creates a bare repository that mirrors the remote's branches
+// and tags
+func initMirror(ctx context.Context, cfg SourceConfig, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	steps := [][]string{
+		{"init", "--bare", "--quiet"},
+		{"remote", "add", "origin", gitRemote(cfg.URL)},
+		{"config", "--replace-all", "remote.origin.fetch", "+refs/heads/*:refs/heads/*"},
+		{"config", "--add", "remote.origin.fetch", "+refs/tags/*:refs/tags/*"},
+	}
+	for _, args := range steps {
+		if _, err := runGit(ctx, cfg, dir, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mirrorHas reports whether the mirror holds commit
+func mirrorHas(ctx context.Context, cfg SourceConfig, dir, commit string) bool {
+	_, err := runGit(ctx, cfg, dir, "cat-file", "-e", commit+"^{commit}")
+	return err == nil
+}
+
+// mirrorKey names the mirror of a remote. Spellings of the same URL share
+// one: scheme and host are lowercased, credentials, a trailing slash and
+// ".git" are dropped, and scp-like URLs are read as ssh:// ones.
+func mirrorKey(raw string) string {
+	normalized := raw
+	if scpLikeURL.MatchString(raw) {
+		userHost, p, _ := strings.Cut(raw, ":")
+		raw = "ssh://" + userHost + "/" + strings.TrimPrefix(p, "/")
+	}
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		u.User = nil
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+		u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+		u.RawQuery, u.Fragment = "", ""
+		normalized = u.String()
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:16])
+}
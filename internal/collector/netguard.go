@@ -0,0 +1,193 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrHostNotAllowed is returned for git hosts outside the allowlist.
+var ErrHostNotAllowed = errors.New("host is not in the allowlist")
+
+// ErrPrivateAddress is returned when a source resolves to an internal address.
+var ErrPrivateAddress = errors.New("source resolves to a private, loopback or link-local address")
+
+// DefaultGitHosts are allowed when no git hosts are configured
+var DefaultGitHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// maxRedirects bounds how many redirects an HTTP download follows
+const maxRedirects = 10
+
+var (
+	netPolicyMu sync.RWMutex
+	gitHosts    = DefaultGitHosts
+	privateNets []*net.IPNet
+)
+
+// carrierNAT is shared address space (RFC 6598) that net.IP does not
+// classify as private
+var carrierNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// SetGitHosts replaces the git host allowlist. An entry "*.example.com"
+// matches any subdomain of example.com. With no hosts, DefaultGitHosts apply.
+func SetGitHosts(hosts ...string) {
+	var allowed []string
+	for _, h := range hosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			allowed = append(allowed, h)
+		}
+	}
+	if len(allowed) == 0 {
+		allowed = DefaultGitHosts
+	}
+	netPolicyMu.Lock()
+	defer netPolicyMu.Unlock()
+	gitHosts = allowed
+}
+
+// AllowPrivateNetworks permits sources in the given CIDR ranges, such as a
+// self-hosted git server on an internal network
+func AllowPrivateNetworks(cidrs ...string) error {
+	var nets []*net.IPNet
+	for _, cidr := range cidrs {
+		if cidr = strings.TrimSpace(cidr); cidr == "" {
+			continue
+		}
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid private network %q: %w", cidr, err)
+		}
+		nets = append(nets, n)
+	}
+	netPolicyMu.Lock()
+	defer netPolicyMu.Unlock()
+	privateNets = append(privateNets, nets...)
+	return nil
+}
+
+// GitHostAllowed reports whether host matches the git host allowlist
+func GitHostAllowed(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	netPolicyMu.RLock()
+	defer netPolicyMu.RUnlock()
+	for _, allowed := range gitHosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceHost extracts the host name from a URL or scp-like git address
+func sourceHost(raw string) (string, error) {
+	if scpLikeURL.MatchString(raw) {
+		host := raw[strings.Index(raw, "@")+1:]
+		return strings.ToLower(host[:strings.Index(host, ":")]), nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if u.Hostname() == "" {
+		return "", errors.New("URL has no host")
+	}
+	return strings.ToLower(u.Hostname()), nil
+}
+
+// checkIP rejects internal addresses outside the allowed private networks
+func checkIP(ip net.IP) error {
+	if ip == nil {
+		return ErrPrivateAddress
+	}
+	internal := ip.IsPrivate() || ip.IsLoopback() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || carrierNAT.Contains(ip)
+	if !internal {
+		return nil
+	}
+
+	netPolicyMu.RLock()
+	defer netPolicyMu.RUnlock()
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrPrivateAddress, ip)
+}
+
+// checkHost resolves a host and rejects it if any address is internal. It
+// guards tools such as git that do their own connecting; HTTP downloads
+// check each connection in httpClient instead.
+func checkHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		return checkIP(ip)
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := checkIP(addr.IP); err != nil {
+			return fmt.Errorf("%s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// checkSourceHost applies checkHost to the host of a source URL
+func checkSourceHost(ctx context.Context, raw string) error {
+	host, err := sourceHost(raw)
+	if err != nil {
+		return invalidSource(err)
+	}
+	return checkHost(ctx, host)
+}
+
+// safeDialer checks the address of every connection after DNS resolution,
+// so neither redirects nor DNS rebinding can reach internal services
+var safeDialer = &net.Dialer{
+	Timeout:   30 * time.Second,
+	KeepAlive: 30 * time.Second,
+	Control: func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		return checkIP(net.ParseIP(host))
+	},
+}
+
+// httpClient is used for all source downloads. Proxies are not used, since a
+// proxy would connect on our behalf without the address check.
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext:           safeDialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+		}
+		return nil
+	},
+}
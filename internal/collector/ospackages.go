@@ -0,0 +1,227 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OS package managers whose databases are inventoried
+const (
+	ManagerDpkg = "dpkg"
+	ManagerApk  = "apk"
+	ManagerRPM  = "rpm"
+)
+
+// OSPackage is a package installed in a container image
+type OSPackage struct {
+	Manager      string // one of the Manager* constants
+	Name         string
+	Version      string
+	Architecture string
+	Source       string // source package, or apk origin, when it differs
+}
+
+// maxPackageDB caps how much of a package database is read
+const maxPackageDB = 256 << 20
+
+// rpmDBDirs are where RPM based images keep their database
+var rpmDBDirs = []string{"var/lib/rpm", "usr/lib/sysimage/rpm"}
+
+// inventoryPackages reads the package databases found in a flattened root
+// filesystem. Databases that exist but cannot be read are reported as
+// warnings rather than failing the collection.
+func inventoryPackages(ctx context.Context, root string) ([]OSPackage, []string) {
+	var pkgs []OSPackage
+	var warnings []string
+	add := func(found []OSPackage, err error) {
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			return
+		}
+		pkgs = append(pkgs, found...)
+	}
+
+	status := filepath.Join(root, "var", "lib", "dpkg", "status")
+	if regularFile(status) {
+		add(readDpkgStatus(status, true))
+	}
+	// Distroless images write one file per package instead
+	if entries, err := os.ReadDir(filepath.Join(root, "var", "lib", "dpkg", "status.d")); err == nil {
+		for _, e := range entries {
+			p := filepath.Join(root, "var", "lib", "dpkg", "status.d", e.Name())
+			if !strings.HasSuffix(e.Name(), ".md5sums") && regularFile(p) {
+				add(readDpkgStatus(p, false))
+			}
+		}
+	}
+
+	installed := filepath.Join(root, "lib", "apk", "db", "installed")
+	if regularFile(installed) {
+		add(readApkInstalled(installed))
+	}
+
+	for _, dir := range rpmDBDirs {
+		db := filepath.Join(root, filepath.FromSlash(dir))
+		if regularFile(filepath.Join(db, "rpmdb.sqlite")) || regularFile(filepath.Join(db, "Packages")) ||
+			regularFile(filepath.Join(db, "Packages.db")) {
+			add(readRPMDB(ctx, db))
+			break
+		}
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].Manager != pkgs[j].Manager {
+			return pkgs[i].Manager < pkgs[j].Manager
+		}
+		return pkgs[i].Name < pkgs[j].Name
+	})
+	return pkgs, warnings
+}
+
+// regularFile reports whether p is a regular file, not following symlinks
+// that an image may point anywhere
+func regularFile(p string) bool {
+	info, err := os.Lstat(p)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// readStanzas splits a file of blank-line separated "Key: value" stanzas,
+// as used by dpkg, and "K:value" ones, as used by apk. Continuation lines
+// are dropped.
+func readStanzas(name string, fn func(map[string]string)) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stanza := map[string]string{}
+	scanner := bufio.NewScanner(io.LimitReader(f, maxPackageDB))
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if len(stanza) > 0 {
+				fn(stanza)
+				stanza = map[string]string{}
+			}
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			stanza[key] = strings.TrimSpace(value)
+		}
+	}
+	if len(stanza) > 0 {
+		fn(stanza)
+	}
+	return scanner.Err()
+}
+
+// readDpkgStatus lists the packages of a dpkg status file. Only packages
+// marked installed count when checkStatus is set.
+func readDpkgStatus(name string, checkStatus bool) ([]OSPackage, error) {
+	var pkgs []OSPackage
+	err := readStanzas(name, func(s map[string]string) {
+		if s["Package"] == "" {
+			return
+		}
+		if checkStatus && !strings.HasSuffix(s["Status"], " installed") {
+			return
+		}
+		// Source may carry its own version: "openssl (3.0.11-1)"
+		source, _, _ := strings.Cut(s["Source"], " ")
+		if source == s["Package"] {
+			source = ""
+		}
+		pkgs = append(pkgs, OSPackage{
+			Manager:      ManagerDpkg,
+			Name:         s["Package"],
+			Version:      s["Version"],
+			Architecture: s["Architecture"],
+			Source:       source,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dpkg database: %w", err)
+	}
+	return pkgs, nil
+}
+
+// readApkInstalled lists the packages of an apk installed database
+func readApkInstalled(name string) ([]OSPackage, error) {
+	var pkgs []OSPackage
+	err := readStanzas(name, func(s map[string]string) {
+		if s["P"] == "" {
+			return
+		}
+		source := s["o"]
+		if source == s["P"] {
+			source = ""
+		}
+		pkgs = append(pkgs, OSPackage{
+			Manager:      ManagerApk,
+			Name:         s["P"],
+			Version:      s["V"],
+			Architecture: s["A"],
+			Source:       source,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read apk database: %w", err)
+	}
+	return pkgs, nil
+}
+
+// RPMAvailable reports whether the rpm tool is installed, which reading
+// RPM databases requires
+func RPMAvailable() bool {
+	_, err := exec.LookPath("rpm")
+	return err == nil
+}
+
+// readRPMDB queries an RPM database with the collector's rpm tool
+func readRPMDB(ctx context.Context, dbpath string) ([]OSPackage, error) {
+	if !RPMAvailable() {
+		return nil, fmt.Errorf("rpm database found but rpm is not installed on the collector")
+	}
+	out, err := exec.CommandContext(ctx, "rpm", "--dbpath", dbpath, "-qa", "--queryformat",
+		`%{NAME}\t%|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}\t%{ARCH}\t%{SOURCERPM}\n`).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rpm database: %w", err)
+	}
+	var pkgs []OSPackage
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 || fields[0] == "gpg-pubkey" {
+			continue
+		}
+		// The source RPM is name-version-release.src.rpm; keep the name
+		source := strings.TrimSuffix(fields[3], ".src.rpm")
+		for i := 0; i < 2; i++ {
+			if j := strings.LastIndex(source, "-"); j > 0 {
+				source = source[:j]
+			}
+		}
+		if fields[3] == "(none)" || source == fields[0] {
+			source = ""
+		}
+		pkgs = append(pkgs, OSPackage{
+			Manager:      ManagerRPM,
+			Name:         fields[0],
+			Version:      fields[1],
+			Architecture: fields[2],
+			Source:       source,
+		})
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,437 @@
+package collector
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/sumdb"
+)
+
+// Package ecosystems
+const (
+	EcosystemGo   = "go"
+	EcosystemNPM  = "npm"
+	EcosystemPyPI = "pypi"
+)
+
+// DefaultRegistries are the registry base URLs used unless SetRegistry
+// points an ecosystem at a mirror
+var DefaultRegistries = map[string]string{
+	EcosystemGo:   "https://proxy.golang.org",
+	EcosystemNPM:  "https://registry.npmjs.org",
+	EcosystemPyPI: "https://pypi.org",
+}
+
+// ErrUnknownEcosystem is returned for ecosystems other than go, npm and pypi.
+var ErrUnknownEcosystem = errors.New("unknown package ecosystem")
+
+var (
+	registriesMu sync.RWMutex
+	registries   = map[string]string{}
+)
+
+var (
+	goModulePattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~/-]*$`)
+	npmNamePattern   = regexp.MustCompile(`^(@[A-Za-z0-9~-][A-Za-z0-9._~-]*/)?[A-Za-z0-9~-][A-Za-z0-9._~-]*$`)
+	pypiNamePattern  = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+	versionPattern   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+_-]*$`)
+	pypiNameSeparate = regexp.MustCompile(`[-_.]+`)
+)
+
+// SetRegistry points an ecosystem at a registry base URL, such as a proxy
+// or a local mirror. An empty URL restores the default. A Go registry must
+// also serve sum.golang.org under /sumdb/, as module proxies do, since
+// module hashes are verified against its signed log.
+func SetRegistry(ecosystem, baseURL string) error {
+	if _, ok := DefaultRegistries[ecosystem]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownEcosystem, ecosystem)
+	}
+	baseURL = strings.TrimSuffix(strings.TrimSpace(baseURL), "/")
+	if baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid %s registry URL %q", ecosystem, baseURL)
+		}
+	}
+	registriesMu.Lock()
+	defer registriesMu.Unlock()
+	if baseURL == "" {
+		delete(registries, ecosystem)
+	} else {
+		registries[ecosystem] = baseURL
+	}
+	return nil
+}
+
+func registry(ecosystem string) string {
+	registriesMu.RLock()
+	defer registriesMu.RUnlock()
+	if r, ok := registries[ecosystem]; ok {
+		return r
+	}
+	return DefaultRegistries[ecosystem]
+}
+
+// packageArtifact is a resolved package download and the checksum its
+// registry publishes for it
+type packageArtifact struct {
+	URL    string
+	SHA256 string // hex, checked by the downloader
+	// verify checks the downloaded file against checksums the downloader
+	// does not handle: Go's h1: hashes, npm's SHA-512 integrity and SHA-1
+	verify func(path string) error
+}
+
+// validatePackage checks a package coordinate
+func validatePackage(cfg SourceConfig) error {
+	if cfg.Package == "" || cfg.Version == "" {
+		return errors.New("package sources need a name and a version")
+	}
+	if !versionPattern.MatchString(cfg.Version) {
+		return fmt.Errorf("invalid package version %q", cfg.Version)
+	}
+	var ok bool
+	switch cfg.Ecosystem {
+	case EcosystemGo:
+		ok = goModulePattern.MatchString(cfg.Package) && !strings.Contains(cfg.Package, "..") && !strings.HasSuffix(cfg.Package, "/")
+	case EcosystemNPM:
+		ok = npmNamePattern.MatchString(cfg.Package) && len(cfg.Package) <= 214
+	case EcosystemPyPI:
+		ok = pypiNamePattern.MatchString(cfg.Package)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownEcosystem, cfg.Ecosystem)
+	}
+	if !ok {
+		return fmt.Errorf("invalid %s package name %q", cfg.Ecosystem, cfg.Package)
+	}
+	return nil
+}
+
+// resolvePackage finds the download of a package version and its checksums
+func resolvePackage(ctx context.Context, cfg SourceConfig) (*packageArtifact, error) {
+	if err := validatePackage(cfg); err != nil {
+		return nil, err
+	}
+	switch cfg.Ecosystem {
+	case EcosystemGo:
+		return resolveGoModule(ctx, cfg.Package, cfg.Version)
+	case EcosystemNPM:
+		return resolveNPM(ctx, cfg.Package, cfg.Version)
+	default:
+		return resolvePyPI(ctx, cfg.Package, cfg.Version)
+	}
+}
+
+// CollectFromPackage downloads a package version from its registry,
+// verifies the checksums the registry publishes and extracts it into the
+// workspace like an archive source
+func CollectFromPackage(ctx context.Context, cfg SourceConfig) (_ *CollectionResult, err error) {
+	pkg, err := resolvePackage(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	dl, err := DefaultDownloader.Fetch(ctx, pkg.URL, "", pkg.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s package %s@%s: %w", cfg.Ecosystem, cfg.Package, cfg.Version, err)
+	}
+	defer os.Remove(dl.Path)
+	if pkg.verify != nil {
+		if err := pkg.verify(dl.Path); err != nil {
+			return nil, fmt.Errorf("%s package %s@%s: %w", cfg.Ecosystem, cfg.Package, cfg.Version, err)
+		}
+	}
+	// Provenance records where the artifact came from
+	cfg.URL = pkg.URL
+	return CollectFromArchiveFile(ctx, cfg, dl.Path, "")
+}
+
+// --- Go modules (GOPROXY protocol) ---
+
+// escapeModulePath applies the module proxy's case encoding: every upper
+// case letter becomes "!" and its lower case form
+func escapeModulePath(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// resolveGoModule downloads the module zip from the proxy. The expected h1:
+// hash comes from the checksum database, read through the proxy and
+// verified against the database's key.
+func resolveGoModule(ctx context.Context, module, version string) (*packageArtifact, error) {
+	base := registry(EcosystemGo)
+	escaped := escapeModulePath(module) + "/@v/" + escapeModulePath(version)
+	want, err := goSumLookup(ctx, base, module, version)
+	if err != nil {
+		return nil, err
+	}
+	return &packageArtifact{
+		URL: base + "/" + escaped + ".zip",
+		verify: func(path string) error {
+			got, err := hashModuleZip(path)
+			if err != nil {
+				return err
+			}
+			if got != want {
+				return fmt.Errorf("%w: checksum database has %s, module zip is %s", ErrChecksumMismatch, want, got)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// goSumDBKey verifies notes signed by sum.golang.org, as in cmd/go
+const goSumDBKey = "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8"
+
+// maxSumDBResponse bounds lookups and tiles read from the checksum database
+const maxSumDBResponse = 1 << 20
+
+var (
+	goSumDBMu     sync.Mutex
+	goSumDBLatest = map[string][]byte{} // latest signed tree seen, by registry
+)
+
+// goSumLookup reads a module version's h1: hash from sum.golang.org as
+// proxied by the module proxy. The record is checked against the database's
+// signed tree, and that tree against the latest one seen through the same
+// proxy, so a proxy cannot vouch for a zip it forged.
+func goSumLookup(ctx context.Context, base, module, version string) (string, error) {
+	client := sumdb.NewClient(&goSumDBOps{ctx: ctx, base: base})
+	lines, err := client.Lookup(module, version)
+	if err != nil {
+		return "", fmt.Errorf("checksum database lookup for %s@%s failed: %w", module, version, err)
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == module && fields[1] == version && strings.HasPrefix(fields[2], "h1:") {
+			return fields[2], nil
+		}
+	}
+	return "", fmt.Errorf("checksum database has no hash for %s@%s", module, version)
+}
+
+// goSumDBOps lets a sumdb.Client read the checksum database through a
+// module proxy. Tiles are not cached; the latest tree is kept in memory.
+type goSumDBOps struct {
+	ctx  context.Context
+	base string
+}
+
+func (o *goSumDBOps) ReadRemote(path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(o.ctx, http.MethodGet, o.base+"/sumdb/sum.golang.org"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("checksum database returned %s for %s", resp.Status, path)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSumDBResponse))
+}
+
+func (o *goSumDBOps) ReadConfig(file string) ([]byte, error) {
+	if file == "key" {
+		return []byte(goSumDBKey), nil
+	}
+	goSumDBMu.Lock()
+	defer goSumDBMu.Unlock()
+	return goSumDBLatest[o.base], nil
+}
+
+func (o *goSumDBOps) WriteConfig(file string, old, new []byte) error {
+	goSumDBMu.Lock()
+	defer goSumDBMu.Unlock()
+	if !bytes.Equal(goSumDBLatest[o.base], old) {
+		return sumdb.ErrWriteConflict
+	}
+	goSumDBLatest[o.base] = new
+	return nil
+}
+
+func (o *goSumDBOps) ReadCache(file string) ([]byte, error) {
+	return nil, os.ErrNotExist
+}
+
+func (o *goSumDBOps) WriteCache(file string, data []byte) {}
+
+func (o *goSumDBOps) Log(msg string) {}
+
+func (o *goSumDBOps) SecurityError(msg string) {
+	log.Printf("[Collector] ❌ Checksum database served by %s failed verification: %s", o.base, msg)
+}
+
+// hashModuleZip computes the h1: hash go.sum records for a module zip: the
+// SHA-256 of a listing of each file's SHA-256 and name, sorted by name
+func hashModuleZip(path string) (string, error) {
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid module zip: %w", err)
+	}
+	defer z.Close()
+
+	files := make([]*zip.File, len(z.File))
+	copy(files, z.File)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	summary := sha256.New()
+	for _, f := range files {
+		if strings.Contains(f.Name, "\n") {
+			return "", fmt.Errorf("invalid module zip: file name %q contains a newline", f.Name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, rc)
+		rc.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(summary, "%x  %s\n", h.Sum(nil), f.Name)
+	}
+	return "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil)), nil
+}
+
+// --- npm ---
+
+type npmPackument struct {
+	Versions map[string]struct {
+		Dist struct {
+			Tarball   string `json:"tarball"`
+			Integrity string `json:"integrity"`
+			Shasum    string `json:"shasum"`
+		} `json:"dist"`
+	} `json:"versions"`
+}
+
+// resolveNPM reads the tarball location and its integrity from the
+// package's registry document
+func resolveNPM(ctx context.Context, name, version string) (*packageArtifact, error) {
+	docURL := registry(EcosystemNPM) + "/" + strings.Replace(name, "/", "%2f", 1)
+	var doc npmPackument
+	if err := getJSON(ctx, docURL, "Accept", "application/vnd.npm.install-v1+json", &doc); err != nil {
+		return nil, fmt.Errorf("npm registry lookup of %s failed: %w", name, err)
+	}
+	v, ok := doc.Versions[version]
+	if !ok {
+		return nil, fmt.Errorf("npm package %s has no version %s", name, version)
+	}
+	if v.Dist.Tarball == "" {
+		return nil, fmt.Errorf("npm package %s@%s has no tarball", name, version)
+	}
+	verify, err := npmVerifier(v.Dist.Integrity, v.Dist.Shasum)
+	if err != nil {
+		return nil, fmt.Errorf("npm package %s@%s: %w", name, version, err)
+	}
+	return &packageArtifact{URL: v.Dist.Tarball, verify: verify}, nil
+}
+
+// npmVerifier checks a tarball against the strongest hash in its Subresource
+// Integrity string, or its SHA-1 shasum when the registry has no integrity
+func npmVerifier(integrity, shasum string) (func(string) error, error) {
+	algorithms := map[string]func() hash.Hash{"sha512": sha512.New, "sha384": sha512.New384, "sha256": sha256.New}
+	for _, algo := range []string{"sha512", "sha384", "sha256"} {
+		for _, entry := range strings.Fields(integrity) {
+			value, found := strings.CutPrefix(entry, algo+"-")
+			if !found {
+				continue
+			}
+			want, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid integrity %q", entry)
+			}
+			return fileDigestVerifier(algorithms[algo], want, entry), nil
+		}
+	}
+	if shasum != "" {
+		want, err := hex.DecodeString(shasum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shasum %q", shasum)
+		}
+		return fileDigestVerifier(sha1.New, want, "sha1 "+shasum), nil
+	}
+	return nil, errors.New("registry publishes no checksum")
+}
+
+func fileDigestVerifier(newHash func() hash.Hash, want []byte, label string) func(string) error {
+	return func(path string) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := newHash()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		if subtle.ConstantTimeCompare(h.Sum(nil), want) != 1 {
+			return fmt.Errorf("%w: expected %s", ErrChecksumMismatch, label)
+		}
+		return nil
+	}
+}
+
+// --- PyPI ---
+
+type pypiRelease struct {
+	URLs []struct {
+		PackageType string `json:"packagetype"`
+		URL         string `json:"url"`
+		Digests     struct {
+			SHA256 string `json:"sha256"`
+		} `json:"digests"`
+	} `json:"urls"`
+}
+
+// resolvePyPI picks the source distribution of a release, or a wheel when
+// there is none, from PyPI's JSON API
+func resolvePyPI(ctx context.Context, name, version string) (*packageArtifact, error) {
+	normalized := strings.ToLower(pypiNameSeparate.ReplaceAllString(name, "-"))
+	apiURL := registry(EcosystemPyPI) + "/pypi/" + normalized + "/" + url.PathEscape(version) + "/json"
+	var release pypiRelease
+	if err := getJSON(ctx, apiURL, "Accept", "application/json", &release); err != nil {
+		return nil, fmt.Errorf("PyPI lookup of %s %s failed: %w", name, version, err)
+	}
+	for _, kind := range []string{"sdist", "bdist_wheel"} {
+		for _, u := range release.URLs {
+			if u.PackageType != kind {
+				continue
+			}
+			if u.Digests.SHA256 == "" {
+				return nil, fmt.Errorf("PyPI publishes no sha256 for %s", u.URL)
+			}
+			return &packageArtifact{URL: u.URL, SHA256: u.Digests.SHA256}, nil
+		}
+	}
+	return nil, fmt.Errorf("PyPI release %s %s has no source distribution or wheel", name, version)
+}
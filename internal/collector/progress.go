@@ -0,0 +1,165 @@
+package collector
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stages reported in Progress
+const (
+	StageGit      = "git"
+	StageDownload = "download"
+	StageExtract  = "extract"
+	StageScan     = "scan"
+)
+
+// progressInterval is the minimum time between two reported events of the
+// same step; the first and last events of each step are always reported
+const progressInterval = 250 * time.Millisecond
+
+// Progress is an event reported while a source is collected
+type Progress struct {
+	Stage          string // one of the Stage* constants
+	Step           string // e.g. git's "Receiving objects" or "layer 2/5" of an image
+	Percent        int    // -1 when the total is unknown
+	Done           int64  // objects, bytes, entries or files so far
+	Total          int64  // 0 when unknown
+	BytesPerSecond int64  // downloads and git transfers
+}
+
+// ProgressFunc receives progress events. Calls never overlap.
+type ProgressFunc func(Progress)
+
+type progressKey struct{}
+
+// WithProgress returns a context under which collections report progress
+// to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, &progressReporter{fn: fn})
+}
+
+// progressReporter throttles events for a ProgressFunc
+type progressReporter struct {
+	fn      ProgressFunc
+	mu      sync.Mutex
+	sent    Progress // last event reported
+	last    time.Time
+	pending *Progress // throttled event not yet reported
+}
+
+// progressFrom returns the context's reporter, or nil; a nil reporter
+// discards events
+func progressFrom(ctx context.Context) *progressReporter {
+	r, _ := ctx.Value(progressKey{}).(*progressReporter)
+	return r
+}
+
+// report passes p on unless an event of the same step was reported less
+// than progressInterval ago, in which case it is kept for flush
+func (r *progressReporter) report(p Progress) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p == r.sent {
+		return
+	}
+	changed := r.sent.Stage != p.Stage || r.sent.Step != p.Step
+	if changed && r.pending != nil {
+		r.send(*r.pending)
+	}
+	if changed || (p.Total > 0 && p.Done >= p.Total) || time.Since(r.last) >= progressInterval {
+		r.send(p)
+		return
+	}
+	r.pending = &p
+}
+
+// flush reports the last throttled event, at the end of a stage
+func (r *progressReporter) flush() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending != nil {
+		r.send(*r.pending)
+	}
+}
+
+func (r *progressReporter) send(p Progress) {
+	r.fn(p)
+	r.sent = p
+	r.last = time.Now()
+	r.pending = nil
+}
+
+// percent is done as a percentage of total, or -1 when total is unknown
+func percent(done, total int64) int {
+	if total <= 0 {
+		return -1
+	}
+	return int(min(done*100/total, 100))
+}
+
+// gitProgressLine matches the progress git prints with --progress, e.g.
+// "Receiving objects:  45% (450/1000), 1.20 MiB | 600.00 KiB/s"
+var gitProgressLine = regexp.MustCompile(`^(?:remote: )?([A-Za-z ]+):\s+(\d+)% \((\d+)/(\d+)\)(?:, [^|]*)?(?:\| ([\d.]+) ([KMG]i)?B/s)?`)
+
+// gitProgress splits git's stderr into progress events, which it reports,
+// and everything else, which it keeps for error messages
+type gitProgress struct {
+	r     *progressReporter
+	line  []byte
+	other strings.Builder
+}
+
+func (g *gitProgress) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b != '\r' && b != '\n' {
+			g.line = append(g.line, b)
+			continue
+		}
+		g.handle(string(g.line))
+		g.line = g.line[:0]
+	}
+	return len(p), nil
+}
+
+func (g *gitProgress) handle(line string) {
+	m := gitProgressLine.FindStringSubmatch(line)
+	if m == nil {
+		if strings.TrimSpace(line) != "" {
+			g.other.WriteString(line + "\n")
+		}
+		return
+	}
+	p := Progress{Stage: StageGit, Step: m[1], Percent: -1}
+	p.Percent, _ = strconv.Atoi(m[2])
+	p.Done, _ = strconv.ParseInt(m[3], 10, 64)
+	p.Total, _ = strconv.ParseInt(m[4], 10, 64)
+	if rate, err := strconv.ParseFloat(m[5], 64); err == nil {
+		switch m[6] {
+		case "Ki":
+			rate *= 1 << 10
+		case "Mi":
+			rate *= 1 << 20
+		case "Gi":
+			rate *= 1 << 30
+		}
+		p.BytesPerSecond = int64(rate)
+	}
+	g.r.report(p)
+}
+
+// String returns the non-progress output
+func (g *gitProgress) String() string {
+	g.handle(string(g.line))
+	g.line = g.line[:0]
+	return g.other.String()
+}
@@ -2,33 +2,73 @@
 package collector
 
 import (
+	"context"
 	"io/fs"
-	"os"
 	"path/filepath"
+
+	"github.com/unarya/unarya/internal/shared/utils"
 )
 
-// scanFiles recursively walks a directory and gathers file info.
-func scanFiles(root string) (*CollectionResult, error) {
-	result := &CollectionResult{}
+// scanFiles recursively walks a directory and gathers file info. Paths
+// excluded by the default excludes, .gitignore or .unaryaignore are skipped,
+// as are symlinks and special files. Every file is hashed for the manifest
+// and counted as StageScan progress.
+func scanFiles(ctx context.Context, cfg SourceConfig, root string) (*CollectionResult, error) {
+	result := &CollectionResult{Root: root, Language: map[string]int64{}, Provenance: newProvenance(cfg)}
+	ignore := newIgnoreMatcher()
+	progress := progressFrom(ctx)
+	defer progress.flush()
 	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel := relSlash(root, path)
 		if d.IsDir() {
+			if rel == "." {
+				ignore.load(root, "")
+				return nil
+			}
+			if ignore.Ignored(rel, true) {
+				return filepath.SkipDir
+			}
+			ignore.load(root, rel)
+			return nil
+		}
+		if !d.Type().IsRegular() || ignore.Ignored(rel, false) {
 			return nil
 		}
-		info, err := os.Stat(path)
+		info, err := d.Info()
 		if err != nil {
 			return err
 		}
+		sum, err := utils.HashFile(path)
+		if err != nil {
+			return err
+		}
+		lfs := isLFSPointer(path, info.Size())
+		class, lang := classify(rel, path, lfs)
 		result.Files = append(result.Files, FileInfo{
-			Name: filepath.Base(path),
-			Path: path,
-			Size: info.Size(),
-			// language detection could be added later
+			Name:       filepath.Base(path),
+			Path:       path,
+			Size:       info.Size(),
+			Language:   lang,
+			SHA256:     sum,
+			Class:      class,
+			LFSPointer: lfs,
 		})
 		result.TotalSize += info.Size()
+		progress.report(Progress{Stage: StageScan, Percent: -1, Done: int64(len(result.Files))})
+		if lang != "" && (class == ClassSource || class == ClassTest) {
+			result.Language[lang] += info.Size()
+		}
 		return nil
 	})
-	return result, err
+	if err != nil {
+		return nil, err
+	}
+	result.MerkleRoot = MerkleRoot(Manifest(result))
+	return result, nil
 }
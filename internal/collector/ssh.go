@@ -0,0 +1,127 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ErrSSHKeyNotFound is returned when an SSH key reference names no stored key.
+var ErrSSHKeyNotFound = errors.New("ssh key not found")
+
+var (
+	sshKeyDirMu sync.RWMutex
+	sshKeyDir   string
+)
+
+// sshKeyRefPattern restricts key references to plain file names
+var sshKeyRefPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SetSSHKeyDir registers the directory holding server-side deploy keys. A
+// reference "name" uses the private key in <dir>/name and the host keys in
+// <dir>/name.known_hosts, or <dir>/known_hosts when that does not exist.
+func SetSSHKeyDir(dir string) {
+	sshKeyDirMu.Lock()
+	defer sshKeyDirMu.Unlock()
+	sshKeyDir = dir
+}
+
+// loadSSHKeyRef reads a stored key and its known_hosts
+func loadSSHKeyRef(ref string) (key, knownHosts string, err error) {
+	if !sshKeyRefPattern.MatchString(ref) {
+		return "", "", invalidSource(fmt.Errorf("invalid ssh key reference %q", ref))
+	}
+	sshKeyDirMu.RLock()
+	dir := sshKeyDir
+	sshKeyDirMu.RUnlock()
+	if dir == "" {
+		return "", "", fmt.Errorf("%w: no key directory configured", ErrSSHKeyNotFound)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ref))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", fmt.Errorf("%w: %s", ErrSSHKeyNotFound, ref)
+		}
+		return "", "", err
+	}
+	hosts, err := os.ReadFile(filepath.Join(dir, ref+".known_hosts"))
+	if os.IsNotExist(err) {
+		hosts, err = os.ReadFile(filepath.Join(dir, "known_hosts"))
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", err
+	}
+	return string(data), string(hosts), nil
+}
+
+// prepareSSH writes the request's SSH key and known_hosts to a private
+// directory outside the workspace and points git at them. The returned
+// cleanup deletes the key material.
+func prepareSSH(cfg *SourceConfig) (cleanup func(), err error) {
+	if cfg.SSHKey == "" && cfg.SSHKeyRef == "" {
+		return func() {}, nil
+	}
+	if cfg.SSHKey != "" && cfg.SSHKeyRef != "" {
+		return nil, invalidSource(errors.New("only one of ssh key and ssh key reference may be set"))
+	}
+
+	key, knownHosts := cfg.SSHKey, cfg.SSHKnownHosts
+	if cfg.SSHKeyRef != "" {
+		var stored string
+		if key, stored, err = loadSSHKeyRef(cfg.SSHKeyRef); err != nil {
+			return nil, err
+		}
+		if knownHosts == "" {
+			knownHosts = stored
+		}
+	}
+	// Host keys are never accepted on first use
+	if strings.TrimSpace(knownHosts) == "" {
+		return nil, invalidSource(errors.New("ssh authentication requires known_hosts for the server"))
+	}
+
+	dir, err := os.MkdirTemp("", "collector-ssh-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup = func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("[Collector] Failed to remove ssh key material %s: %v", dir, err)
+		}
+	}
+
+	keyFile := filepath.Join(dir, "id")
+	hostsFile := filepath.Join(dir, "known_hosts")
+	// ssh rejects keys without a trailing newline
+	if err := os.WriteFile(keyFile, []byte(strings.TrimSpace(key)+"\n"), 0600); err != nil {
+		cleanup()
+		return nil, err
+	}
+	if err := os.WriteFile(hostsFile, []byte(knownHosts), 0600); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	cfg.sshCommand = strings.Join([]string{
+		"ssh", "-F", "/dev/null",
+		"-i", shellQuote(keyFile),
+		"-o", "IdentitiesOnly=yes",
+		"-o", "IdentityAgent=none",
+		"-o", "BatchMode=yes",
+		"-o", "StrictHostKeyChecking=yes",
+		"-o", "UserKnownHostsFile=" + shellQuote(hostsFile),
+		"-o", "GlobalKnownHostsFile=/dev/null",
+	}, " ")
+	return cleanup, nil
+}
+
+// shellQuote quotes s for the shell git runs GIT_SSH_COMMAND with
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
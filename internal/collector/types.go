@@ -1,30 +1,86 @@
 package collector
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/unarya/unarya/internal/shared/changeset"
+)
 
 // SourceConfig defines the configuration for a source collection request.
 type SourceConfig struct {
-	Type      string // "git", "archive", "url"
+	Type      string // "git", "archive", "url", "local", "package", "image"
 	URL       string
 	Branch    string
 	Token     string
 	LocalPath string
+	// Git sources: Commit or Tag pin the revision instead of Branch, Subpath
+	// limits the checkout to one directory, and Depth sets how much history
+	// is fetched (0 for the latest commit only, negative for all of it)
+	Commit  string
+	Tag     string
+	Subpath string
+	Depth   int
+	// Submodules checks out git submodules recursively; LFS fetches Git LFS
+	// objects instead of leaving pointer files
+	Submodules bool
+	LFS        bool
+	// BaseRef, a branch, tag or commit, turns a git collection into a pull
+	// request collection: the head is checked out as usual and the files
+	// and lines changed since its merge base with BaseRef are listed in
+	// CollectionResult.Changes. The full history of both is fetched.
+	BaseRef string
+	// HistoryDepth > 0 mines up to that many commits, up to MaxHistoryDepth,
+	// for per-file churn and authorship in CollectionResult.History
+	HistoryDepth int
+	// SSH git sources authenticate with SSHKey, a private key, and verify
+	// the server against SSHKnownHosts. SSHKeyRef names a key stored on the
+	// collector instead (see SetSSHKeyDir).
+	SSHKey        string
+	SSHKnownHosts string
+	SSHKeyRef     string
+	sshCommand    string // GIT_SSH_COMMAND while a request runs
+	// NestedDepth enables recursive extraction of archives found inside an
+	// archive source, up to MaxNestedDepth levels. 0 disables it.
+	NestedDepth int
+	// SHA256, when set, is the expected checksum of an archive, URL or
+	// image download
+	SHA256 string
+	// Snapshot copies a local source into the workspace instead of reading
+	// it in place
+	Snapshot bool
+	// Package sources name a package version in a registry (see
+	// SetRegistry): Ecosystem is "go", "npm" or "pypi"
+	Ecosystem string
+	Package   string
+	Version   string
 }
 
 // FileInfo represents a collected file's metadata.
 type FileInfo struct {
-	Name     string
-	Path     string
-	Size     int64
-	Language string
+	Name       string
+	Path       string
+	Size       int64
+	Language   string
+	SHA256     string
+	Class      string // one of the Class* constants
+	LFSPointer bool   // Git LFS pointer whose content was not fetched
 }
 
 // CollectionResult summarizes the result of a collection operation.
 type CollectionResult struct {
+	Root      string // workspace directory holding the collected files
+	Commit    string // resolved commit SHA for git sources
 	Files     []FileInfo
 	TotalSize int64
-	Language  []string
-	Error     error
+	Language  map[string]int64 // bytes of source and test files per language
+	// Provenance says where the files came from; MerkleRoot is the
+	// MerkleRoot of their Manifest
+	Provenance Provenance
+	MerkleRoot string
+	Changes    *changeset.ChangeSet // set when SourceConfig.BaseRef is
+	History    *History             // set when SourceConfig.HistoryDepth is
+	Image      *ImageInfo           // set for image sources
+	Error      error
 }
 
 // ErrInvalidSourceType is returned when the source type is unsupported.
@@ -32,5 +88,5 @@ var ErrInvalidSourceType = errors.New("invalid source type")
 
 // SupportedTypes returns all acceptable source types.
 func SupportedTypes() []string {
-	return []string{"git", "archive", "url"}
+	return []string{"git", "archive", "url", "local", "package", "image"}
 }
@@ -2,34 +2,84 @@ package collector
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/url"
+	"regexp"
 	"strings"
 )
 
+// scpLikeURL matches the user@host:path form git accepts for SSH
+var scpLikeURL = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[^/-]`)
+
+// ErrInvalidSource marks errors in the request itself, such as a malformed
+// URL or ref, as opposed to failures to fetch the source it names
+var ErrInvalidSource = errors.New("invalid source")
+
+// invalidSourceError wraps a validation error in ErrInvalidSource, keeping
+// its message
+type invalidSourceError struct{ err error }
+
+func (e *invalidSourceError) Error() string   { return e.err.Error() }
+func (e *invalidSourceError) Unwrap() []error { return []error{ErrInvalidSource, e.err} }
+
+// invalidSource marks err, if any, as an ErrInvalidSource
+func invalidSource(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &invalidSourceError{err: err}
+}
+
 // ValidateSource ensures the input source configuration is safe and valid.
+// Its errors match ErrInvalidSource.
 func ValidateSource(cfg SourceConfig) error {
+	return invalidSource(validateSource(cfg))
+}
+
+func validateSource(cfg SourceConfig) error {
+	if cfg.Type == "package" {
+		// Registries are configured on the collector; only the coordinate
+		// comes from the request
+		return validatePackage(cfg)
+	}
 	if cfg.URL == "" {
 		return errors.New("missing source URL")
 	}
 
-	if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+	if cfg.Type == "local" {
+		// The allowlist is checked by the collector, which owns it
+		_, err := LocalPath(cfg.URL)
+		return err
+	}
+
+	// scp-like git URLs (git@host:org/repo.git) are not URIs
+	if cfg.Type != "git" || !scpLikeURL.MatchString(cfg.URL) {
+		if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+			return errors.New("invalid URL format")
+		}
+	}
+
+	host, err := sourceHost(cfg.URL)
+	if err != nil {
 		return errors.New("invalid URL format")
 	}
+	// Names are resolved when the source is fetched; literal addresses can
+	// be rejected now
+	if ip := net.ParseIP(host); ip != nil {
+		if err := checkIP(ip); err != nil {
+			return err
+		}
+	}
 
 	switch cfg.Type {
 	case "git":
-		if !strings.Contains(cfg.URL, "github.com") &&
-			!strings.Contains(cfg.URL, "gitlab.com") &&
-			!strings.Contains(cfg.URL, "bitbucket.org") {
-			return errors.New("unsupported git provider")
-		}
-	case "archive":
-		if !strings.HasSuffix(cfg.URL, ".zip") &&
-			!strings.HasSuffix(cfg.URL, ".tar.gz") &&
-			!strings.HasSuffix(cfg.URL, ".tgz") {
-			return errors.New("invalid archive format")
+		if !GitHostAllowed(host) {
+			return fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
 		}
-	case "url":
+		return validateGitRef(cfg)
+	case "archive", "url", "image":
+		// Archive formats are detected from the downloaded content
 		if !strings.HasPrefix(cfg.URL, "http://") &&
 			!strings.HasPrefix(cfg.URL, "https://") {
 			return errors.New("invalid http source")
@@ -0,0 +1,24 @@
+package collector
+
+import (
+	"os"
+)
+
+// prepareWorkspace gives cfg a fresh per-request directory when the caller
+// did not choose one. The returned cleanup removes that directory if the
+// collection fails; caller-provided paths are left alone.
+func prepareWorkspace(cfg *SourceConfig, prefix string) (cleanup func(*error), err error) {
+	if cfg.LocalPath != "" {
+		return func(*error) {}, os.MkdirAll(cfg.LocalPath, 0755)
+	}
+	dir, err := os.MkdirTemp("", prefix+"-*")
+	if err != nil {
+		return nil, err
+	}
+	cfg.LocalPath = dir
+	return func(errp *error) {
+		if *errp != nil {
+			os.RemoveAll(dir)
+		}
+	}, nil
+}
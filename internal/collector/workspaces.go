@@ -0,0 +1,383 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unarya/unarya/internal/shared/utils"
+)
+
+// ErrWorkspaceNotFound is returned for unknown workspace IDs.
+var ErrWorkspaceNotFound = errors.New("workspace not found")
+
+// ErrWorkspaceQuota is returned when no space can be freed for a workspace.
+var ErrWorkspaceQuota = errors.New("workspace disk quota exceeded")
+
+// ErrWorkspaceNotReady is returned when acquiring a workspace whose
+// collection is still running.
+var ErrWorkspaceNotReady = errors.New("workspace collection has not finished")
+
+// workspacePrefix starts every workspace ID and directory name
+const workspacePrefix = "ws"
+
+// metaSuffix ends the name of the file that keeps a workspace's Meta next
+// to its directory
+const metaSuffix = ".meta"
+
+// Workspace is a directory a collection was written to
+type Workspace struct {
+	ID         string
+	Path       string // directory owned by the manager
+	Root       string // collected files; outside Path for in-place local sources
+	SourceType string
+	Jobs       []string // jobs holding a reference
+	Size       int64    // bytes on disk below Path
+	Ready      bool     // false while the collection is still running
+	Meta       []byte   // description of the collection, set by Commit
+	CreatedAt  time.Time
+	LastUsed   time.Time
+}
+
+// WorkspaceManager hands out per-collection directories below a base
+// directory. A workspace lives while jobs reference it and is deleted when
+// the last reference is released, or once it has gone unreferenced and
+// unused for the TTL. Collections are refused once the quota is reached and
+// nothing can be evicted.
+type WorkspaceManager struct {
+	mu         sync.Mutex
+	base       string
+	ttl        time.Duration
+	quota      int64 // bytes; 0 disables
+	workspaces map[string]*Workspace
+}
+
+// NewWorkspaceManager creates base if needed and adopts workspaces left by
+// a previous process, which then expire after the TTL like any other.
+func NewWorkspaceManager(base string, ttl time.Duration, quota int64) (*WorkspaceManager, error) {
+	if err := os.MkdirAll(base, 0755); err != nil {
+		return nil, err
+	}
+	m := &WorkspaceManager{base: base, ttl: ttl, quota: quota, workspaces: make(map[string]*Workspace)}
+
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), workspacePrefix+"-") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(base, e.Name())
+		meta, _ := os.ReadFile(path + metaSuffix)
+		m.workspaces[e.Name()] = &Workspace{
+			ID:        e.Name(),
+			Path:      path,
+			Root:      path,
+			Size:      dirSize(path),
+			Ready:     true,
+			Meta:      meta,
+			CreatedAt: info.ModTime(),
+			LastUsed:  info.ModTime(),
+		}
+	}
+	if len(m.workspaces) > 0 {
+		log.Printf("[Collector] Adopted %d workspaces from %s", len(m.workspaces), base)
+	}
+	return m, nil
+}
+
+// Create reserves a new workspace, referenced by jobID when it is set
+func (m *WorkspaceManager) Create(sourceType, jobID string) (*Workspace, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.makeRoom(time.Now(), 1); err != nil {
+		return nil, err
+	}
+	id := utils.NewID(workspacePrefix)
+	ws := &Workspace{
+		ID:         id,
+		Path:       filepath.Join(m.base, id),
+		SourceType: sourceType,
+		CreatedAt:  time.Now(),
+		LastUsed:   time.Now(),
+	}
+	ws.Root = ws.Path
+	if jobID != "" {
+		ws.Jobs = []string{jobID}
+	}
+	if err := os.Mkdir(ws.Path, 0755); err != nil {
+		return nil, err
+	}
+	m.workspaces[id] = ws
+	c := *ws
+	return &c, nil
+}
+
+// Commit records a finished collection, its root directory and meta, which
+// Acquire hands to the jobs that reuse the collection. Collections running
+// side by side only count against the quota once their size is known, so
+// it is checked again here: a collection that does not fit even after
+// evicting idle workspaces is refused and its workspace removed.
+func (m *WorkspaceManager) Commit(id, root string, meta []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ws, ok := m.workspaces[id]
+	if !ok {
+		return ErrWorkspaceNotFound
+	}
+	ws.Size = dirSize(ws.Path)
+	if err := m.makeRoom(time.Now(), 0); err != nil {
+		if rmErr := m.remove(ws); rmErr != nil {
+			log.Printf("[Collector] Failed to remove workspace %s: %v", ws.ID, rmErr)
+		}
+		return err
+	}
+	if err := os.WriteFile(ws.Path+metaSuffix, meta, 0644); err != nil {
+		return err
+	}
+	ws.Root = root
+	ws.Ready = true
+	ws.Meta = meta
+	ws.LastUsed = time.Now()
+	return nil
+}
+
+// Acquire adds a job's reference to a finished workspace and returns it
+func (m *WorkspaceManager) Acquire(id, jobID string) (*Workspace, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ws, ok := m.workspaces[id]
+	if !ok {
+		return nil, ErrWorkspaceNotFound
+	}
+	if !ws.Ready {
+		return nil, ErrWorkspaceNotReady
+	}
+	ws.LastUsed = time.Now()
+	if !containsString(ws.Jobs, jobID) {
+		ws.Jobs = append(ws.Jobs, jobID)
+	}
+	c := *ws
+	c.Jobs = append([]string(nil), ws.Jobs...)
+	return &c, nil
+}
+
+// Release drops a job's reference to a finished workspace and deletes the
+// workspace when none are left. It returns the references that remain.
+// Remove is the only way to delete a workspace regardless of references.
+func (m *WorkspaceManager) Release(id, jobID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ws, ok := m.workspaces[id]
+	if !ok {
+		return 0, ErrWorkspaceNotFound
+	}
+	if !ws.Ready {
+		return len(ws.Jobs), ErrWorkspaceNotReady
+	}
+	jobs := ws.Jobs[:0]
+	for _, j := range ws.Jobs {
+		if j != jobID {
+			jobs = append(jobs, j)
+		}
+	}
+	ws.Jobs = jobs
+	ws.LastUsed = time.Now()
+	if len(ws.Jobs) > 0 {
+		return len(ws.Jobs), nil
+	}
+	return 0, m.remove(ws)
+}
+
+// Remove deletes a workspace regardless of references, e.g. after a failed
+// collection
+func (m *WorkspaceManager) Remove(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ws, ok := m.workspaces[id]
+	if !ok {
+		return ErrWorkspaceNotFound
+	}
+	return m.remove(ws)
+}
+
+// remove deletes the directory the manager owns; a root outside it, such as
+// a local source read in place, is never touched
+func (m *WorkspaceManager) remove(ws *Workspace) error {
+	if err := os.RemoveAll(ws.Path); err != nil {
+		return err
+	}
+	os.Remove(ws.Path + metaSuffix)
+	delete(m.workspaces, ws.ID)
+	return nil
+}
+
+// List returns the workspaces referenced by jobID, or all of them when it is
+// empty, oldest first
+func (m *WorkspaceManager) List(jobID string) []Workspace {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var list []Workspace
+	for _, ws := range m.workspaces {
+		if jobID != "" && !containsString(ws.Jobs, jobID) {
+			continue
+		}
+		c := *ws
+		c.Jobs = append([]string(nil), ws.Jobs...)
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
+
+// Usage returns the bytes used by all workspaces and the quota
+func (m *WorkspaceManager) Usage() (used, quota int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.used(), m.quota
+}
+
+// ExpiresAt is when an unused workspace becomes eligible for collection;
+// zero while jobs reference it
+func (m *WorkspaceManager) ExpiresAt(ws Workspace) time.Time {
+	if m.ttl <= 0 || len(ws.Jobs) > 0 {
+		return time.Time{}
+	}
+	return ws.LastUsed.Add(m.ttl)
+}
+
+// RemovePending deletes workspaces whose collection never finished;
+// registered as a shutdown hook
+func (m *WorkspaceManager) RemovePending(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ws := range m.workspaces {
+		if ws.Ready {
+			continue
+		}
+		if err := m.remove(ws); err != nil {
+			log.Printf("[Collector] Failed to remove workspace %s: %v", ws.ID, err)
+			continue
+		}
+		log.Printf("[Collector] Removed unfinished workspace %s", ws.ID)
+	}
+}
+
+// GC deletes finished workspaces that no job references and that were not
+// used within the TTL, and returns how many were removed
+func (m *WorkspaceManager) GC(now time.Time) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.expire(now)
+}
+
+func (m *WorkspaceManager) expire(now time.Time) int {
+	if m.ttl <= 0 {
+		return 0
+	}
+	removed := 0
+	for _, ws := range m.workspaces {
+		if !ws.Ready || len(ws.Jobs) > 0 || now.Sub(ws.LastUsed) < m.ttl {
+			continue
+		}
+		if err := m.remove(ws); err != nil {
+			log.Printf("[Collector] Failed to remove expired workspace %s: %v", ws.ID, err)
+			continue
+		}
+		removed++
+	}
+	return removed
+}
+
+// RunGC collects expired workspaces every interval until ctx is done
+func (m *WorkspaceManager) RunGC(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if n := m.GC(now); n > 0 {
+				log.Printf("[Collector] Removed %d expired workspaces", n)
+			}
+		}
+	}
+}
+
+// makeRoom frees space until extra more bytes fit the quota: expired
+// workspaces go first, then unreferenced ones, least recently used first
+func (m *WorkspaceManager) makeRoom(now time.Time, extra int64) error {
+	if m.quota <= 0 || m.used()+extra <= m.quota {
+		return nil
+	}
+	m.expire(now)
+
+	var idle []*Workspace
+	for _, ws := range m.workspaces {
+		if ws.Ready && len(ws.Jobs) == 0 {
+			idle = append(idle, ws)
+		}
+	}
+	sort.Slice(idle, func(i, j int) bool { return idle[i].LastUsed.Before(idle[j].LastUsed) })
+	for _, ws := range idle {
+		if m.used()+extra <= m.quota {
+			break
+		}
+		if err := m.remove(ws); err != nil {
+			log.Printf("[Collector] Failed to evict workspace %s: %v", ws.ID, err)
+			continue
+		}
+		log.Printf("[Collector] Evicted workspace %s to stay within the disk quota", ws.ID)
+	}
+	if used := m.used(); used+extra > m.quota {
+		return fmt.Errorf("%w: %d of %d bytes in use", ErrWorkspaceQuota, used, m.quota)
+	}
+	return nil
+}
+
+func (m *WorkspaceManager) used() int64 {
+	var total int64
+	for _, ws := range m.workspaces {
+		total += ws.Size
+	}
+	return total
+}
+
+// dirSize totals the regular files below dir
+func dirSize(dir string) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/unarya/unarya/internal/shared/utils"
+)
+
+// Job statuses
+const (
+	JobQueued      = "queued"
+	JobRunning     = "running"
+	JobSuccess     = "success"
+	JobFailed      = "failed"
+	JobCancelled   = "cancelled"
+	JobInterrupted = "interrupted"
+)
+
+// watchBuffer is how many events a slow watcher may fall behind before
+// further progress events are dropped for it
+const watchBuffer = 64
+
+// JobStore keeps pipeline jobs in memory and checkpoints them to a JSON file
+// so queued work survives a restart. Changes to a job are published to its
+// watchers.
+type JobStore struct {
+	mu       sync.Mutex
+	path     string
+	jobs     map[string]*Job
+	watchers map[string][]chan JobEvent
+}
+
+// NewJobStore opens the store at path, loading any previous checkpoint
+func NewJobStore(path string) (*JobStore, error) {
+	s := &JobStore{path: path, jobs: make(map[string]*Job), watchers: make(map[string][]chan JobEvent)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job store: %w", err)
+	}
+
+	var jobs []*Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode job store %s: %w", path, err)
+	}
+	for _, job := range jobs {
+		s.jobs[job.ID] = job
+	}
+	log.Printf("[JobStore] Loaded %d job(s) from %s", len(jobs), path)
+	return s, nil
+}
+
+// Put inserts or replaces a job
+func (s *JobStore) Put(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.UpdatedAt = time.Now()
+	s.jobs[job.ID] = &job
+}
+
+// Create inserts a new job, reporting false when one with its ID exists
+func (s *JobStore) Create(job Job) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.ID]; ok {
+		return false
+	}
+	job.UpdatedAt = time.Now()
+	s.jobs[job.ID] = &job
+	return true
+}
+
+// SetStatus updates the status and details of an existing job
+func (s *JobStore) SetStatus(id, status, details string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	job.Status = status
+	job.Details = details
+	if JobFinished(status) {
+		job.Stage = ""
+	}
+	job.UpdatedAt = time.Now()
+	s.publish(job, nil)
+}
+
+// SetStage records the pipeline stage a running job has reached
+func (s *JobStore) SetStage(id, stage string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	job.Stage = stage
+	job.UpdatedAt = time.Now()
+	s.publish(job, nil)
+}
+
+// ReportProgress publishes collection progress to a job's watchers
+func (s *JobStore) ReportProgress(id string, progress JobProgress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[id]; ok {
+		s.publish(job, &progress)
+	}
+}
+
+// Watch subscribes to a job's events. It returns the job as it is now and
+// a channel of later events that is closed once the job has finished;
+// stop unsubscribes. A watcher that falls behind misses events rather than
+// holding up the job.
+func (s *JobStore) Watch(id string) (job Job, events <-chan JobEvent, stop func(), ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, nil, nil, false
+	}
+	ch := make(chan JobEvent, watchBuffer)
+	if JobFinished(j.Status) {
+		close(ch)
+		return *j, ch, func() {}, true
+	}
+	s.watchers[id] = append(s.watchers[id], ch)
+	stop = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, w := range s.watchers[id] {
+			if w == ch {
+				s.watchers[id] = append(s.watchers[id][:i], s.watchers[id][i+1:]...)
+				close(ch)
+				break
+			}
+		}
+		if len(s.watchers[id]) == 0 {
+			delete(s.watchers, id)
+		}
+	}
+	return *j, ch, stop, true
+}
+
+// CloseWatchers ends every watch, at shutdown
+func (s *JobStore) CloseWatchers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, watchers := range s.watchers {
+		for _, ch := range watchers {
+			close(ch)
+		}
+		delete(s.watchers, id)
+	}
+}
+
+// publish sends an event for job to its watchers, closing their channels
+// once the job has finished. Callers hold s.mu.
+func (s *JobStore) publish(job *Job, progress *JobProgress) {
+	watchers := s.watchers[job.ID]
+	if len(watchers) == 0 {
+		return
+	}
+	event := JobEvent{
+		JobID:    job.ID,
+		Status:   job.Status,
+		Stage:    job.Stage,
+		Time:     job.UpdatedAt,
+		Progress: progress,
+	}
+	finished := JobFinished(job.Status)
+	if finished {
+		event.Details = job.Details
+	}
+	if progress != nil {
+		event.Time = time.Now()
+	}
+	for _, ch := range watchers {
+		select {
+		case ch <- event:
+		default:
+			if !finished {
+				continue
+			}
+			// The final event displaces the oldest one a slow watcher has
+			// not read; only publish sends, so there is room after that
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- event
+		}
+		if finished {
+			close(ch)
+		}
+	}
+	if finished {
+		delete(s.watchers, job.ID)
+	}
+}
+
+// Get returns a copy of the job with the given ID
+func (s *JobStore) Get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// List returns the jobs in any of the given statuses, oldest first.
+// With no statuses every job is returned.
+func (s *JobStore) List(statuses ...string) []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Job
+	for _, job := range s.jobs {
+		if len(statuses) == 0 || contains(statuses, job.Status) {
+			out = append(out, *job)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Checkpoint writes every job to disk, replacing the previous checkpoint
+// atomically. Credentials embedded in repository URLs are left out, like
+// tokens and keys.
+func (s *JobStore) Checkpoint() error {
+	s.mu.Lock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		copy := *job
+		copy.Request.RepositoryURL = utils.StripCredentials(copy.Request.RepositoryURL)
+		jobs = append(jobs, &copy)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := utils.EnsureDir(filepath.Dir(s.path)); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// JobFinished reports whether status is final for the current process
+func JobFinished(status string) bool {
+	switch status {
+	case JobSuccess, JobFailed, JobCancelled, JobInterrupted:
+		return true
+	}
+	return false
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,8 @@ import (
 	_ "fmt"
 	"log"
 	"time"
+
+	"github.com/unarya/unarya/internal/shared/utils"
 )
 
 // Orchestrator coordinates multi-service pipelines
@@ -17,7 +19,7 @@ type Orchestrator struct {
 // ExecutePipeline runs the full multi-step orchestration
 func (o *Orchestrator) ExecutePipeline(req *Request) (*Result, error) {
 	start := time.Now()
-	log.Printf("[Orchestrator] Starting pipeline for %s\n", req.RepositoryURL)
+	log.Printf("[Orchestrator] Starting pipeline for %s\n", utils.RedactURL(req.RepositoryURL))
 
 	// 1. Update state
 	o.StateManager.Update("collector", "running")
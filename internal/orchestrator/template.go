@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Pipeline stages
+const (
+	StageCollector    = "collector"
+	StageParser       = "parser"
+	StageAI           = "ai"
+	StageSecurityScan = "security_scan"
+)
+
+// DefaultTemplate is used when a request does not name a template
+const DefaultTemplate = "full"
+
+// PipelineTemplate names the ordered stages a pipeline runs
+type PipelineTemplate struct {
+	Name   string
+	Stages []string
+}
+
+var templates = map[string]PipelineTemplate{
+	"full":     {Name: "full", Stages: []string{StageCollector, StageParser, StageAI, StageSecurityScan}},
+	"analysis": {Name: "analysis", Stages: []string{StageCollector, StageParser, StageAI}},
+	"security": {Name: "security", Stages: []string{StageCollector, StageSecurityScan}},
+}
+
+// ResolveTemplate looks up a pipeline template by name
+func ResolveTemplate(name string) (PipelineTemplate, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	tmpl, ok := templates[name]
+	if !ok {
+		return PipelineTemplate{}, fmt.Errorf("unknown pipeline template %q (available: %v)", name, TemplateNames())
+	}
+	return tmpl, nil
+}
+
+// TemplateNames lists the available templates
+func TemplateNames() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has reports whether the template runs the given stage
+func (t PipelineTemplate) Has(stage string) bool {
+	return contains(t.Stages, stage)
+}
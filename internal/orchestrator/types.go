@@ -4,10 +4,32 @@ import "time"
 
 // Request defines a full orchestration request
 type Request struct {
-	RepositoryURL string
+	RepositoryURL string // persisted without user information
 	Branch        string
-	Token         string
-	SourceType    string // "git", "archive", "url"
+	Token         string `json:"-"` // never persisted
+	SourceType    string // "git", "archive", "url", "local", "package", "image"
+	Template      string // pipeline template name, DefaultTemplate when empty
+	NestedDepth   int    // archive sources: levels of nested archives to unpack
+	Snapshot      bool   // local sources: copy the directory before analysis
+	Commit        string // git sources: full commit SHA instead of a branch
+	Tag           string // git sources: tag instead of a branch
+	Subpath       string // git sources: analyze only this directory
+	Depth         int    // git sources: history depth, 0 latest commit, -1 full
+	Submodules    bool   // git sources: check out submodules recursively
+	LFS           bool   // git sources: fetch Git LFS objects
+	SSHKey        string `json:"-"` // ssh git sources: deploy key, never persisted
+	Credentials   bool   // Token, SSHKey or URL user information was given; a resumed job needs them again
+	SSHKnownHosts string // ssh git sources: known_hosts lines for the server
+	SSHKeyRef     string // ssh git sources: key stored on the collector
+	SHA256        string // archive, url and image sources: expected checksum
+	BaseRef       string // git sources: pull request base, analyze changes only
+	HistoryDepth  int    // git sources: commits to mine for churn, 0 disables
+
+	PackageEcosystem string // package sources: "go", "npm" or "pypi"
+	PackageName      string // package sources: module, package or project
+	PackageVersion   string // package sources: exact version
+
+	WorkspaceID string // collector workspace to analyze instead of collecting
 }
 
 // ParsedData represents output from the Parser service
@@ -50,3 +72,34 @@ type State struct {
 	Status    string // "pending", "running", "success", "failed"
 	Timestamp time.Time
 }
+
+// Job is a pipeline run tracked across the orchestrator's lifetime
+type Job struct {
+	ID        string
+	Request   Request
+	Status    string // "queued", "running", "success", "failed", "cancelled", "interrupted"
+	Stage     string // pipeline stage running, while the job runs
+	Details   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// JobEvent is a change to a job, delivered to its watchers (see JobStore.Watch)
+type JobEvent struct {
+	JobID    string
+	Status   string
+	Stage    string
+	Details  string // set once the job has finished
+	Time     time.Time
+	Progress *JobProgress // collection progress relayed from the collector
+}
+
+// JobProgress is a collector progress event
+type JobProgress struct {
+	Stage          string // "git", "download", "extract" or "scan"
+	Step           string
+	Percent        int // -1 when the total is unknown
+	Done           int64
+	Total          int64 // 0 when unknown
+	BytesPerSecond int64
+}
@@ -33,8 +33,12 @@ type ProjectStructure struct {
 	Files     []string
 }
 
-// CodeMetrics captures basic statistics about the codebase.
+// CodeMetrics captures basic statistics about the codebase, or about one
+// file when Path is set, as the parser service reports them. Path is
+// relative to the source root with forward slashes, the key of the
+// collector's manifest and file history.
 type CodeMetrics struct {
+	Path       string
 	TotalFiles int
 	TotalLines int
 	Functions  int
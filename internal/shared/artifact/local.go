@@ -0,0 +1,103 @@
+package artifact
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore keeps artifacts in a directory, as sha256/<ab>/<rest of hex>
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates dir if needed
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+func (s *LocalStore) path(hexDigest string) string {
+	return filepath.Join(s.dir, "sha256", hexDigest[:2], hexDigest[2:])
+}
+
+// Put stores r; content that is already stored is not written again
+func (s *LocalStore) Put(ctx context.Context, r io.Reader) (string, error) {
+	f, digest, _, err := spool(r)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	hexDigest, _ := ParseDigest(digest)
+	target := s.path(hexDigest)
+	if _, err := os.Stat(target); err == nil {
+		return digest, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", err
+	}
+
+	// Write next to the target and rename so readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".put-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, f); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", err
+	}
+	return digest, nil
+}
+
+// Get opens a stored artifact
+func (s *LocalStore) Get(ctx context.Context, digest string) (io.ReadCloser, error) {
+	hexDigest, err := ParseDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.path(hexDigest))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newVerifier(f, hexDigest), nil
+}
+
+// Exists reports whether an artifact is stored
+func (s *LocalStore) Exists(ctx context.Context, digest string) (bool, error) {
+	hexDigest, err := ParseDigest(digest)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(s.path(hexDigest))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete removes an artifact
+func (s *LocalStore) Delete(ctx context.Context, digest string) error {
+	hexDigest, err := ParseDigest(digest)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(hexDigest)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package artifact
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PackDir stores a directory as a gzipped tar and returns its digest. The
+// archive holds directories and regular files only, with fixed timestamps
+// and ownership, so identical trees produce identical digests.
+func PackDir(ctx context.Context, store Store, dir string) (string, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeSnapshot(ctx, pw, dir))
+	}()
+	digest, err := store.Put(ctx, pr)
+	pr.CloseWithError(err)
+	return digest, err
+}
+
+func writeSnapshot(ctx context.Context, w io.Writer, dir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    filepath.ToSlash(rel),
+			Mode:    int64(info.Mode().Perm()),
+			ModTime: time.Unix(0, 0),
+			Format:  tar.FormatPAX,
+		}
+		switch {
+		case d.IsDir():
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+			return tw.WriteHeader(hdr)
+		case d.Type().IsRegular():
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = info.Size()
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.CopyN(tw, f, hdr.Size)
+			return err
+		default:
+			// Symlinks and special files are not part of a snapshot
+			return nil
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Unpack extracts a snapshot made by PackDir into dest. Entries other than
+// directories and regular files, and paths leaving dest, are rejected.
+func Unpack(ctx context.Context, store Store, digest, dest string) error {
+	rc, err := store.Get(ctx, digest)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	gz, err := gzip.NewReader(rc)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot %s: %w", digest, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid snapshot %s: %w", digest, err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name := filepath.FromSlash(strings.TrimSuffix(hdr.Name, "/"))
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid snapshot %s: unsafe entry %q", digest, hdr.Name)
+		}
+		target := filepath.Join(dest, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode).Perm()&0755|0600)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("invalid snapshot %s: unsupported entry %q", digest, hdr.Name)
+		}
+	}
+	// Drain the stream so the digest is checked at EOF
+	if _, err := io.Copy(io.Discard, rc); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Materialize returns a directory holding a stage's input. With a digest
+// and a store the snapshot is unpacked into a temporary directory that
+// cleanup removes; otherwise path is used as is, as on a shared volume.
+func Materialize(ctx context.Context, store Store, digest, path string) (dir string, cleanup func(), err error) {
+	if digest == "" {
+		return path, func() {}, nil
+	}
+	if store == nil {
+		if path != "" {
+			log.Printf("[Artifact] No artifact store configured; reading %s from the shared path", digest)
+			return path, func() {}, nil
+		}
+		return "", nil, fmt.Errorf("artifact %s requested but no artifact store is configured", digest)
+	}
+
+	dir, err = os.MkdirTemp("", "artifact-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+	if err := Unpack(ctx, store, digest, dir); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to fetch artifact %s: %w", digest, err)
+	}
+	return dir, cleanup, nil
+}
@@ -0,0 +1,122 @@
+// Package artifact stores content-addressed blobs, such as workspace
+// snapshots, so services can exchange files without sharing a filesystem.
+package artifact
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/unarya/unarya/internal/shared/config"
+)
+
+// ErrNotFound is returned when no artifact has the requested digest.
+var ErrNotFound = errors.New("artifact not found")
+
+// ErrDigestMismatch is returned when stored content does not match its digest.
+var ErrDigestMismatch = errors.New("artifact content does not match its digest")
+
+// digestPrefix starts every digest; only SHA-256 is supported
+const digestPrefix = "sha256:"
+
+var digestPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// Store keeps blobs addressed by the SHA-256 digest of their content
+type Store interface {
+	// Put stores the content of r and returns its digest
+	Put(ctx context.Context, r io.Reader) (string, error)
+	// Get opens the content with the given digest. Reading it to the end
+	// fails with ErrDigestMismatch if the stored content was altered.
+	Get(ctx context.Context, digest string) (io.ReadCloser, error)
+	// Exists reports whether an artifact is stored
+	Exists(ctx context.Context, digest string) (bool, error)
+	// Delete removes an artifact; deleting a missing one is not an error
+	Delete(ctx context.Context, digest string) error
+}
+
+// Backends selectable with ARTIFACT_STORE
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Open returns the store configured by cfg, or nil when none is
+func Open(cfg *config.Config) (Store, error) {
+	switch cfg.ArtifactStore {
+	case "":
+		return nil, nil
+	case BackendLocal:
+		return NewLocalStore(cfg.ArtifactDir)
+	case BackendS3:
+		return NewS3Store(S3Config{
+			Endpoint:     cfg.ArtifactS3Endpoint,
+			Bucket:       cfg.ArtifactS3Bucket,
+			Region:       cfg.ArtifactS3Region,
+			AccessKey:    cfg.ArtifactS3AccessKey,
+			SecretKey:    cfg.ArtifactS3SecretKey,
+			SessionToken: cfg.ArtifactS3SessionToken,
+		})
+	default:
+		return nil, fmt.Errorf("unknown artifact store %q", cfg.ArtifactStore)
+	}
+}
+
+// ParseDigest validates a digest and returns its hex part
+func ParseDigest(digest string) (string, error) {
+	if !digestPattern.MatchString(digest) {
+		return "", fmt.Errorf("invalid artifact digest %q", digest)
+	}
+	return strings.TrimPrefix(digest, digestPrefix), nil
+}
+
+// spool copies r to a temporary file while hashing it, since both backends
+// need the digest before the content can be stored. The caller removes the
+// returned file.
+func spool(r io.Reader) (_ *os.File, digest string, size int64, err error) {
+	f, err := os.CreateTemp("", "artifact-*")
+	if err != nil {
+		return nil, "", 0, err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	h := sha256.New()
+	if size, err = io.Copy(io.MultiWriter(f, h), r); err != nil {
+		return nil, "", 0, err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return nil, "", 0, err
+	}
+	return f, digestPrefix + hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// verifier checks content against its digest as it is read
+type verifier struct {
+	io.ReadCloser
+	hash   hash.Hash
+	expect string
+}
+
+func newVerifier(rc io.ReadCloser, hexDigest string) io.ReadCloser {
+	return &verifier{ReadCloser: rc, hash: sha256.New(), expect: hexDigest}
+}
+
+func (v *verifier) Read(p []byte) (int, error) {
+	n, err := v.ReadCloser.Read(p)
+	v.hash.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(v.hash.Sum(nil)) != v.expect {
+		return n, ErrDigestMismatch
+	}
+	return n, err
+}
@@ -0,0 +1,205 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Record is one audited API call
+type Record struct {
+	Time       time.Time `json:"time"`
+	Subject    string    `json:"subject"`    // JWT subject or API key ID
+	Credential string    `json:"credential"` // jwt, api_key or none
+	Method     string    `json:"method"`
+	Repository string    `json:"repository,omitempty"`
+	JobID      string    `json:"job_id,omitempty"`
+	Outcome    string    `json:"outcome"` // gRPC status code
+	Error      string    `json:"error,omitempty"`
+}
+
+// Query selects records; zero values match everything
+type Query struct {
+	Subject string
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+}
+
+func (q Query) matches(r Record) bool {
+	if q.Subject != "" && r.Subject != q.Subject {
+		return false
+	}
+	if !q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !r.Time.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Log is an append-only JSON-lines audit file. When the file grows past
+// maxBytes it is rotated to path.1, path.2, ... keeping maxFiles old files.
+type Log struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// Open opens or creates the audit log at path
+func Open(path string, maxBytes int64, maxFiles int) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	l := &Log{path: path, maxBytes: maxBytes, maxFiles: maxFiles}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// Append writes a record to the end of the log
+func (l *Log) Append(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxBytes > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// rotate shifts path.N-1 → path.N, ..., path → path.1 and starts a new file
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	os.Remove(l.rotated(l.maxFiles))
+	for i := l.maxFiles - 1; i >= 1; i-- {
+		os.Rename(l.rotated(i), l.rotated(i+1))
+	}
+	if l.maxFiles > 0 {
+		if err := os.Rename(l.path, l.rotated(1)); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	} else {
+		os.Remove(l.path)
+	}
+	return l.open()
+}
+
+func (l *Log) rotated(i int) string {
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
+
+// Query returns matching records, oldest first. When a limit is set the most
+// recent records are kept. Files are opened under the lock, which keeps the
+// set consistent across a rotation, and read after it is released so that
+// appends are not held up.
+func (l *Log) Query(q Query) ([]Record, error) {
+	files, err := l.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer closeAll(files)
+
+	var records []Record
+	for _, f := range files {
+		err := scanFile(f, func(r Record) {
+			if q.matches(r) {
+				records = append(records, r)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if q.Limit > 0 && len(records) > q.Limit {
+		records = records[len(records)-q.Limit:]
+	}
+	return records, nil
+}
+
+// snapshot opens the rotated files and the current one, oldest first
+func (l *Log) snapshot() ([]*os.File, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	paths := []string{l.path}
+	for i := 1; i <= l.maxFiles; i++ {
+		paths = append([]string{l.rotated(i)}, paths...)
+	}
+
+	var files []*os.File
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			closeAll(files)
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func closeAll(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+func scanFile(f *os.File, fn func(Record)) error {
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue // torn write from a crash, or a line being appended
+		}
+		fn(r)
+	}
+	return scanner.Err()
+}
+
+// Close flushes and closes the log file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
@@ -0,0 +1,210 @@
+// Package changeset describes the files and lines changed between two
+// commits, as collected for pull request analysis, and carries them to the
+// parser and scanner.
+package changeset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Statuses of a changed file
+const (
+	Added    = "added"
+	Modified = "modified"
+	Deleted  = "deleted"
+	Renamed  = "renamed"
+	Copied   = "copied"
+)
+
+// Hunk is a changed range of lines. Lines counts may be 0: an insertion has
+// no old lines and a deletion no new ones.
+type Hunk struct {
+	OldStart int `json:"old_start"`
+	OldLines int `json:"old_lines"`
+	NewStart int `json:"new_start"`
+	NewLines int `json:"new_lines"`
+}
+
+// File is one changed file. Path is the path at the head; for deleted files
+// it is the path at the base.
+type File struct {
+	Path    string `json:"path"`
+	OldPath string `json:"old_path,omitempty"` // renamed and copied files
+	Status  string `json:"status"`
+	Binary  bool   `json:"binary,omitempty"`
+	Hunks   []Hunk `json:"hunks,omitempty"`
+}
+
+// ChangeSet is the difference between the merge base of a base ref and the
+// head, with paths relative to the collection root
+type ChangeSet struct {
+	BaseRef    string `json:"base_ref"`
+	BaseCommit string `json:"base_commit"`
+	HeadCommit string `json:"head_commit"`
+	MergeBase  string `json:"merge_base"`
+	Files      []File `json:"files"`
+}
+
+// Encode serializes a change set for stage requests. A nil change set
+// encodes as the empty string.
+func (c *ChangeSet) Encode() string {
+	if c == nil {
+		return ""
+	}
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+// Decode reads a change set produced by Encode. An empty string yields nil,
+// meaning the whole tree is analyzed.
+func Decode(s string) (*ChangeSet, error) {
+	if s == "" {
+		return nil, nil
+	}
+	c := &ChangeSet{}
+	if err := json.Unmarshal([]byte(s), c); err != nil {
+		return nil, fmt.Errorf("failed to decode change set: %w", err)
+	}
+	return c, nil
+}
+
+// file returns the entry for a slash-separated path present at the head
+func (c *ChangeSet) file(rel string) *File {
+	for i := range c.Files {
+		if c.Files[i].Path == rel && c.Files[i].Status != Deleted {
+			return &c.Files[i]
+		}
+	}
+	return nil
+}
+
+// Changed reports whether a file at the head was added or modified. Every
+// path counts as changed for a nil change set.
+func (c *ChangeSet) Changed(rel string) bool {
+	return c == nil || c.file(rel) != nil
+}
+
+// LineChanged reports whether a line of a file at the head, counted from 1,
+// was added or modified. Every line counts as changed for a nil change set
+// and in added files.
+func (c *ChangeSet) LineChanged(rel string, line int) bool {
+	if c == nil {
+		return true
+	}
+	f := c.file(rel)
+	if f == nil {
+		return false
+	}
+	if f.Status == Added || f.Binary {
+		return true
+	}
+	for _, h := range f.Hunks {
+		if line >= h.NewStart && line < h.NewStart+h.NewLines {
+			return true
+		}
+	}
+	return false
+}
+
+// Paths lists the files present at the head
+func (c *ChangeSet) Paths() []string {
+	var paths []string
+	for _, f := range c.Files {
+		if f.Status != Deleted {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// ParseNameStatus reads the output of git diff --name-status -z
+func ParseNameStatus(out []byte) ([]File, error) {
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	var files []File
+	for i := 0; i < len(fields) && fields[0] != ""; {
+		code := fields[i]
+		if code == "" || i+1 >= len(fields) {
+			return nil, fmt.Errorf("malformed name-status entry %q", code)
+		}
+		f := File{Path: fields[i+1]}
+		i += 2
+		switch code[0] {
+		case 'A':
+			f.Status = Added
+		case 'D':
+			f.Status = Deleted
+		case 'M', 'T':
+			f.Status = Modified
+		case 'R', 'C':
+			if i >= len(fields) {
+				return nil, fmt.Errorf("malformed name-status entry %q", code)
+			}
+			f.Status = Renamed
+			if code[0] == 'C' {
+				f.Status = Copied
+			}
+			f.OldPath, f.Path = f.Path, fields[i]
+			i++
+		default:
+			return nil, fmt.Errorf("unsupported change status %q", code)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// AddHunks reads a git diff --unified=0 patch made with the same options as
+// the name-status listing of files, and attaches each file's hunks. git
+// prints both in the same order, so sections are matched by position.
+func AddHunks(files []File, patch []byte) error {
+	section := -1
+	sc := bufio.NewScanner(bytes.NewReader(patch))
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			section++
+			if section >= len(files) {
+				return fmt.Errorf("patch has more files than the change list (%d)", len(files))
+			}
+		case section < 0:
+			continue
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			files[section].Binary = true
+		case strings.HasPrefix(line, "@@ "):
+			m := hunkHeader.FindStringSubmatch(line)
+			if m == nil {
+				return fmt.Errorf("malformed hunk header %q", line)
+			}
+			files[section].Hunks = append(files[section].Hunks, Hunk{
+				OldStart: atoi(m[1]),
+				OldLines: count(m[2]),
+				NewStart: atoi(m[3]),
+				NewLines: count(m[4]),
+			})
+		}
+	}
+	return sc.Err()
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// count reads a hunk length, which git omits when it is 1
+func count(s string) int {
+	if s == "" {
+		return 1
+	}
+	return atoi(s)
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +16,38 @@ type Config struct {
 	JWTSecret   string
 	APIKey      string
 	Env         string
+
+	// DrainTimeout bounds how long shutdown waits for in-flight jobs
+	DrainTimeout time.Duration
+
+	// Audit log location and rotation
+	AuditLogPath     string
+	AuditLogMaxBytes int64
+	AuditLogMaxFiles int
+
+	// AdminSubjects may call the AdminService's control and audit RPCs:
+	// JWT subjects or API key IDs (see auth.APIKeyID)
+	AdminSubjects []string
+
+	// Source network policy: git hosts sources may come from and private
+	// networks (CIDRs) sources may resolve to
+	GitAllowedHosts        []string
+	AllowedPrivateNetworks []string
+
+	// Package registry base URLs; empty for the public registries
+	GoProxyURL     string
+	NPMRegistryURL string
+	PyPIURL        string
+
+	// Artifact store shared by the stages: "" (disabled), "local" or "s3"
+	ArtifactStore          string
+	ArtifactDir            string
+	ArtifactS3Endpoint     string
+	ArtifactS3Bucket       string
+	ArtifactS3Region       string
+	ArtifactS3AccessKey    string
+	ArtifactS3SecretKey    string
+	ArtifactS3SessionToken string
 }
 
 // Load reads .env and system variables into Config struct
@@ -25,6 +60,29 @@ func Load() *Config {
 		JWTSecret:   getEnv("JWT_SECRET", "supersecret"),
 		APIKey:      getEnv("API_KEY", ""),
 		Env:         getEnv("ENV", "development"),
+
+		DrainTimeout: getEnvDuration("SHUTDOWN_DRAIN_TIMEOUT", 30*time.Second),
+
+		AuditLogPath:     getEnv("AUDIT_LOG_PATH", "data/audit.jsonl"),
+		AuditLogMaxBytes: int64(getEnvInt("AUDIT_LOG_MAX_BYTES", 10<<20)),
+		AuditLogMaxFiles: getEnvInt("AUDIT_LOG_MAX_FILES", 5),
+		AdminSubjects:    getEnvList("ADMIN_SUBJECTS"),
+
+		GitAllowedHosts:        getEnvList("GIT_ALLOWED_HOSTS"),
+		AllowedPrivateNetworks: getEnvList("ALLOWED_PRIVATE_NETWORKS"),
+
+		GoProxyURL:     getEnv("PACKAGE_REGISTRY_GO", ""),
+		NPMRegistryURL: getEnv("PACKAGE_REGISTRY_NPM", ""),
+		PyPIURL:        getEnv("PACKAGE_REGISTRY_PYPI", ""),
+
+		ArtifactStore:          getEnv("ARTIFACT_STORE", ""),
+		ArtifactDir:            getEnv("ARTIFACT_DIR", "data/artifacts"),
+		ArtifactS3Endpoint:     getEnv("ARTIFACT_S3_ENDPOINT", ""),
+		ArtifactS3Bucket:       getEnv("ARTIFACT_S3_BUCKET", ""),
+		ArtifactS3Region:       getEnv("ARTIFACT_S3_REGION", ""),
+		ArtifactS3AccessKey:    getEnv("ARTIFACT_S3_ACCESS_KEY", ""),
+		ArtifactS3SecretKey:    getEnv("ARTIFACT_S3_SECRET_KEY", ""),
+		ArtifactS3SessionToken: getEnv("ARTIFACT_S3_SESSION_TOKEN", ""),
 	}
 
 	log.Printf("[Config] Loaded for service: %s", cfg.ServiceName)
@@ -37,3 +95,34 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getEnvList reads a comma-separated list, dropping empty entries
+func getEnvList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func getEnvInt(key string, fallback int) int {
+	if val := os.Getenv(key); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			return n
+		}
+		log.Printf("[Config] Invalid integer for %s: %q, using %d", key, val, fallback)
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d
+		}
+		log.Printf("[Config] Invalid duration for %s: %q, using %s", key, val, fallback)
+	}
+	return fallback
+}
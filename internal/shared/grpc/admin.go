@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/unarya/unarya/internal/shared/audit"
+	"github.com/unarya/unarya/internal/shared/auth"
+	"github.com/unarya/unarya/internal/shared/config"
+	"github.com/unarya/unarya/lib/proto/pb/adminpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultAuditQueryLimit caps QueryAudit results when the caller sets no limit
+const defaultAuditQueryLimit = 1000
+
+// AdminPolicy decides who may pause and resume intake and read the audit
+// log. Callers are identified as in the audit log; unverified tokens never
+// qualify.
+type AdminPolicy struct {
+	jwt      *auth.JWTService
+	subjects map[string]bool
+}
+
+// NewAdminPolicy admits cfg.AdminSubjects; with none configured the
+// protected RPCs are refused to everyone
+func NewAdminPolicy(cfg *config.Config) *AdminPolicy {
+	p := &AdminPolicy{jwt: auth.NewJWTService(cfg.JWTSecret), subjects: make(map[string]bool)}
+	for _, subject := range cfg.AdminSubjects {
+		p.subjects[subject] = true
+	}
+	return p
+}
+
+// authorize checks the caller behind ctx
+func (p *AdminPolicy) authorize(ctx context.Context) error {
+	if p == nil {
+		return status.Error(codes.PermissionDenied, "admin RPCs are disabled")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	subject, credential := auth.Identify(md, p.jwt)
+	if credential == auth.CredentialNone || strings.HasPrefix(subject, "unverified:") {
+		return status.Error(codes.Unauthenticated, "admin RPCs require a valid token or API key")
+	}
+	if !p.subjects[subject] {
+		return status.Errorf(codes.PermissionDenied, "%s is not an admin", subject)
+	}
+	return nil
+}
+
+// AdminServer implements adminpb.AdminServiceServer on top of an Intake
+type AdminServer struct {
+	adminpb.UnimplementedAdminServiceServer
+	intake  *Intake
+	auditor *Auditor
+	policy  *AdminPolicy
+}
+
+// RegisterAdmin exposes intake controls and, when auditor is non-nil, audit
+// queries on the given server. Everything but GetIntakeStatus, which health
+// probes use, requires a subject admitted by policy.
+func RegisterAdmin(server *grpc.Server, intake *Intake, auditor *Auditor, policy *AdminPolicy) {
+	adminpb.RegisterAdminServiceServer(server, &AdminServer{intake: intake, auditor: auditor, policy: policy})
+}
+
+// PauseIntake stops the service from accepting new jobs
+func (a *AdminServer) PauseIntake(ctx context.Context, req *adminpb.IntakeRequest) (*adminpb.IntakeStatus, error) {
+	if err := a.policy.authorize(ctx); err != nil {
+		return nil, err
+	}
+	a.intake.Pause()
+	log.Printf("[Admin] Intake paused")
+	return a.status(), nil
+}
+
+// ResumeIntake reopens intake after a pause
+func (a *AdminServer) ResumeIntake(ctx context.Context, req *adminpb.IntakeRequest) (*adminpb.IntakeStatus, error) {
+	if err := a.policy.authorize(ctx); err != nil {
+		return nil, err
+	}
+	a.intake.Resume()
+	log.Printf("[Admin] Intake resumed")
+	return a.status(), nil
+}
+
+// GetIntakeStatus reports the current intake state
+func (a *AdminServer) GetIntakeStatus(ctx context.Context, req *adminpb.IntakeRequest) (*adminpb.IntakeStatus, error) {
+	return a.status(), nil
+}
+
+// QueryAudit returns audit records matching the subject and time range
+func (a *AdminServer) QueryAudit(ctx context.Context, req *adminpb.AuditQuery) (*adminpb.AuditRecords, error) {
+	if err := a.policy.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if a.auditor == nil {
+		return nil, status.Error(codes.FailedPrecondition, "audit log is not enabled")
+	}
+
+	q := audit.Query{Subject: req.Subject, Limit: int(req.Limit)}
+	if req.SinceUnix > 0 {
+		q.Since = time.Unix(req.SinceUnix, 0)
+	}
+	if req.UntilUnix > 0 {
+		q.Until = time.Unix(req.UntilUnix, 0)
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultAuditQueryLimit
+	}
+
+	records, err := a.auditor.Log().Query(q)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read audit log: %v", err)
+	}
+
+	resp := &adminpb.AuditRecords{Records: make([]*adminpb.AuditRecord, 0, len(records))}
+	for _, r := range records {
+		resp.Records = append(resp.Records, &adminpb.AuditRecord{
+			TimeUnixNano: r.Time.UnixNano(),
+			Subject:      r.Subject,
+			Credential:   r.Credential,
+			Method:       r.Method,
+			Repository:   r.Repository,
+			JobId:        r.JobID,
+			Outcome:      r.Outcome,
+			Error:        r.Error,
+		})
+	}
+	return resp, nil
+}
+
+func (a *AdminServer) status() *adminpb.IntakeStatus {
+	paused, draining, inFlight := a.intake.Status()
+	return &adminpb.IntakeStatus{
+		Paused:   paused,
+		Draining: draining,
+		InFlight: int32(inFlight),
+	}
+}
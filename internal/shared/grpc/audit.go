@@ -0,0 +1,164 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/unarya/unarya/internal/shared/audit"
+	"github.com/unarya/unarya/internal/shared/auth"
+	"github.com/unarya/unarya/internal/shared/config"
+	"github.com/unarya/unarya/internal/shared/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// AuditSource is implemented by requests whose audited fields, the
+// repository and job ID, sit in a nested message
+type AuditSource interface {
+	AuditSource() proto.Message
+}
+
+// AuditResult is implemented by streamed messages of which only some
+// describe the call's outcome; it returns nil for the others
+type AuditResult interface {
+	AuditResult() proto.Message
+}
+
+// Auditor records every API call to an audit log
+type Auditor struct {
+	log *audit.Log
+	jwt *auth.JWTService
+}
+
+// NewAuditor returns an Auditor writing to auditLog. Bearer tokens are
+// verified with jwtSvc when identifying the caller.
+func NewAuditor(auditLog *audit.Log, jwtSvc *auth.JWTService) *Auditor {
+	return &Auditor{log: auditLog, jwt: jwtSvc}
+}
+
+// OpenAuditor opens the audit log described by cfg
+func OpenAuditor(cfg *config.Config) (*Auditor, error) {
+	auditLog, err := audit.Open(cfg.AuditLogPath, cfg.AuditLogMaxBytes, cfg.AuditLogMaxFiles)
+	if err != nil {
+		return nil, err
+	}
+	return NewAuditor(auditLog, auth.NewJWTService(cfg.JWTSecret)), nil
+}
+
+// Log returns the underlying audit log
+func (a *Auditor) Log() *audit.Log {
+	return a.log
+}
+
+// Close closes the audit log
+func (a *Auditor) Close() error {
+	return a.log.Close()
+}
+
+// UnaryInterceptor records the caller, target and outcome of each call
+func (a *Auditor) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	a.record(ctx, info.FullMethod, req, resp, err)
+	return resp, err
+}
+
+// StreamInterceptor is the streaming counterpart of UnaryInterceptor. The
+// first message received stands for the request and the last one sent for
+// the response.
+func (a *Auditor) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	stream := &auditedStream{ServerStream: ss}
+	err := handler(srv, stream)
+	a.record(ss.Context(), info.FullMethod, stream.req, stream.resp, err)
+	return err
+}
+
+// auditedStream keeps the messages of a stream that the audit record is
+// taken from
+type auditedStream struct {
+	grpc.ServerStream
+	req, resp interface{}
+}
+
+func (s *auditedStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.req == nil {
+		s.req = m
+	}
+	return err
+}
+
+// SendMsg keeps the last message sent, or the last result of messages
+// implementing AuditResult
+func (s *auditedStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	if r, ok := m.(AuditResult); ok {
+		if result := r.AuditResult(); result != nil {
+			s.resp = result
+		}
+		return nil
+	}
+	s.resp = m
+	return nil
+}
+
+func (a *Auditor) record(ctx context.Context, method string, req, resp interface{}, err error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	subject, credential := auth.Identify(md, a.jwt)
+
+	req = sourceOf(req)
+	r := audit.Record{
+		Time:       time.Now().UTC(),
+		Subject:    subject,
+		Credential: credential,
+		Method:     method,
+		Repository: repositoryOf(req),
+		JobID:      jobIDOf(req, resp),
+		Outcome:    status.Code(err).String(),
+	}
+	if err != nil {
+		r.Error = status.Convert(err).Message()
+	}
+	if err := a.log.Append(r); err != nil {
+		log.Printf("[Audit] ❌ Failed to record %s: %v", method, err)
+	}
+}
+
+// sourceOf unwraps requests implementing AuditSource
+func sourceOf(req interface{}) interface{} {
+	if r, ok := req.(AuditSource); ok {
+		if source := r.AuditSource(); source != nil {
+			return source
+		}
+	}
+	return req
+}
+
+// repositoryOf extracts the repository a request targets, if any, without
+// credentials embedded in its URL
+func repositoryOf(req interface{}) string {
+	switch r := req.(type) {
+	case interface{ GetRepositoryUrl() string }:
+		return utils.RedactURL(r.GetRepositoryUrl())
+	case interface{ GetUrl() string }:
+		return utils.RedactURL(r.GetUrl())
+	case interface{ GetSourcePath() string }:
+		return r.GetSourcePath()
+	}
+	return ""
+}
+
+// jobIDOf extracts the job a call belongs to from its request or response
+func jobIDOf(req, resp interface{}) string {
+	for _, m := range []interface{}{req, resp} {
+		if j, ok := m.(interface{ GetJobId() string }); ok && j.GetJobId() != "" {
+			return j.GetJobId()
+		}
+	}
+	return ""
+}
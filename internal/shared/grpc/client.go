@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -21,3 +22,17 @@ func NewGRPCClient(target string) (*grpc.ClientConn, error) {
 
 	return conn, nil
 }
+
+// CheckReachable connects conn and waits until it is ready or ctx expires
+func CheckReachable(ctx context.Context, conn *grpc.ClientConn) error {
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("%s not reachable (state: %s)", conn.Target(), state)
+		}
+	}
+}
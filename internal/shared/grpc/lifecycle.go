@@ -0,0 +1,291 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultDrainTimeout = 30 * time.Second
+	abortGracePeriod    = 5 * time.Second
+	adminServicePrefix  = "/adminpb.AdminService/"
+)
+
+var (
+	// ErrIntakePaused is returned for new requests while intake is paused.
+	ErrIntakePaused = errors.New("intake paused")
+	// ErrDraining is returned for new requests once shutdown has started.
+	ErrDraining = errors.New("service is shutting down")
+)
+
+// Intake gates new requests and tracks the ones in flight so a server can
+// drain them before it stops.
+type Intake struct {
+	mu       sync.Mutex
+	paused   bool
+	draining bool
+	inFlight int
+	idle     chan struct{}
+	exempt   map[string]bool
+
+	abortCtx context.Context
+	abort    context.CancelFunc
+}
+
+// NewIntake returns an open Intake with no jobs in flight
+func NewIntake() *Intake {
+	ctx, cancel := context.WithCancel(context.Background())
+	idle := make(chan struct{})
+	close(idle)
+	return &Intake{idle: idle, abortCtx: ctx, abort: cancel}
+}
+
+// Pause stops accepting new requests until Resume is called
+func (i *Intake) Pause() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.paused = true
+}
+
+// Resume reopens intake after Pause
+func (i *Intake) Resume() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.paused = false
+}
+
+// Status reports the pause and drain flags and the number of jobs in flight
+func (i *Intake) Status() (paused, draining bool, inFlight int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.paused, i.draining, i.inFlight
+}
+
+// Draining reports whether shutdown has started
+func (i *Intake) Draining() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.draining
+}
+
+// Begin registers a new job, failing when intake is paused or draining.
+// Every successful Begin must be paired with End.
+func (i *Intake) Begin() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.draining {
+		return ErrDraining
+	}
+	if i.paused {
+		return ErrIntakePaused
+	}
+	if i.inFlight == 0 {
+		i.idle = make(chan struct{})
+	}
+	i.inFlight++
+	return nil
+}
+
+// End marks a job started with Begin as finished
+func (i *Intake) End() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.inFlight--
+	if i.inFlight == 0 {
+		close(i.idle)
+	}
+}
+
+// Drain closes intake for good and waits until every job has finished or
+// ctx expires.
+func (i *Intake) Drain(ctx context.Context) error {
+	i.mu.Lock()
+	i.draining = true
+	idle := i.idle
+	i.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Abort cancels the context of every job still in flight
+func (i *Intake) Abort() {
+	i.abort()
+}
+
+// JobContext derives a context that is also cancelled when the intake is
+// aborted. Handlers that run long jobs outside the request (background
+// resumes, for example) should use it.
+func (i *Intake) JobContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	stop := context.AfterFunc(i.abortCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// Exempt lets methods that only observe, such as watch streams, bypass
+// intake like the AdminService: they are served while intake is paused or
+// draining and do not hold up a drain.
+func (i *Intake) Exempt(methods ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.exempt == nil {
+		i.exempt = make(map[string]bool)
+	}
+	for _, m := range methods {
+		i.exempt[m] = true
+	}
+}
+
+func (i *Intake) exempted(method string) bool {
+	if strings.HasPrefix(method, adminServicePrefix) {
+		return true
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.exempt[method]
+}
+
+// UnaryInterceptor rejects requests while intake is closed and tracks the rest
+func (i *Intake) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if i.exempted(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	if err := i.Begin(); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer i.End()
+
+	ctx, cancel := i.JobContext(ctx)
+	defer cancel()
+	return handler(ctx, req)
+}
+
+// StreamInterceptor is the streaming counterpart of UnaryInterceptor
+func (i *Intake) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if i.exempted(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	if err := i.Begin(); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer i.End()
+
+	ctx, cancel := i.JobContext(ss.Context())
+	defer cancel()
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// NewServer creates a gRPC server gated by intake, with the AdminService
+// already registered and guarded by admins. When auditor is non-nil every
+// call, including the ones intake rejects, is written to its audit log.
+func NewServer(intake *Intake, auditor *Auditor, admins *AdminPolicy, opts ...grpc.ServerOption) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{intake.UnaryInterceptor}
+	stream := []grpc.StreamServerInterceptor{intake.StreamInterceptor}
+	if auditor != nil {
+		unary = append([]grpc.UnaryServerInterceptor{auditor.UnaryInterceptor}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{auditor.StreamInterceptor}, stream...)
+	}
+
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}, opts...)
+	server := grpc.NewServer(opts...)
+	RegisterAdmin(server, intake, auditor, admins)
+	return server
+}
+
+// ShutdownOptions configures ServeWithShutdown.
+type ShutdownOptions struct {
+	Intake       *Intake
+	DrainTimeout time.Duration
+	// OnShutdown hooks run once in-flight jobs have finished or been aborted,
+	// before the server stops. Use them for checkpointing and cleanup.
+	OnShutdown []func(ctx context.Context)
+}
+
+// ServeWithShutdown serves until SIGINT or SIGTERM, then drains in-flight
+// jobs for up to DrainTimeout, aborts whatever is left, runs the shutdown
+// hooks and stops the server.
+func ServeWithShutdown(server *grpc.Server, lis net.Listener, opts ShutdownOptions) error {
+	if opts.DrainTimeout <= 0 {
+		opts.DrainTimeout = defaultDrainTimeout
+	}
+
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(lis) }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-sigCtx.Done():
+	}
+	stop()
+
+	log.Printf("[Shutdown] Signal received, draining jobs (timeout %s)", opts.DrainTimeout)
+	drainCtx, cancel := context.WithTimeout(context.Background(), opts.DrainTimeout)
+	defer cancel()
+
+	if err := opts.Intake.Drain(drainCtx); err != nil {
+		_, _, inFlight := opts.Intake.Status()
+		log.Printf("[Shutdown] Drain timed out, aborting %d job(s)", inFlight)
+		opts.Intake.Abort()
+
+		graceCtx, graceCancel := context.WithTimeout(context.Background(), abortGracePeriod)
+		if err := opts.Intake.Drain(graceCtx); err != nil {
+			log.Printf("[Shutdown] Jobs still running after abort: %v", err)
+		}
+		graceCancel()
+	}
+
+	hookCtx, hookCancel := context.WithTimeout(context.Background(), abortGracePeriod)
+	for _, hook := range opts.OnShutdown {
+		hook(hookCtx)
+	}
+	hookCancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(abortGracePeriod):
+		server.Stop()
+	}
+
+	log.Printf("[Shutdown] Server stopped")
+	return nil
+}
@@ -0,0 +1,245 @@
+package repoconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the repository-level configuration file detected at the
+// workspace root
+const FileName = ".unarya.yml"
+
+// maxFileSize caps how much of the file is read
+const maxFileSize = 64 << 10
+
+// Known values accepted by the schema
+var (
+	RuleSets   = []string{"secrets", "dependencies", "permissions", "vulnerabilities"}
+	Severities = []string{"low", "medium", "high", "critical"}
+	Artifacts  = []string{"dockerfile", "compose", "k8s", "ci"}
+	Languages  = []string{"Go", "Python", "JavaScript", "TypeScript", "Java", "C/C++", "Rust", "PHP", "Ruby"}
+)
+
+// Config is the parsed contents of a .unarya.yml file
+type Config struct {
+	Version int           `yaml:"version" json:"version"`
+	Ignore  []string      `yaml:"ignore" json:"ignore,omitempty"`
+	Parser  ParserConfig  `yaml:"parser" json:"parser"`
+	Scanner ScannerConfig `yaml:"scanner" json:"scanner"`
+	Deploy  DeployConfig  `yaml:"deploy" json:"deploy"`
+}
+
+// ParserConfig overrides parser behaviour
+type ParserConfig struct {
+	Language string `yaml:"language" json:"language,omitempty"` // primary language, skips detection
+}
+
+// ScannerConfig selects security scanner rules
+type ScannerConfig struct {
+	RuleSets          []string `yaml:"rulesets" json:"rulesets,omitempty"`                     // empty means all
+	SeverityThreshold string   `yaml:"severity_threshold" json:"severity_threshold,omitempty"` // drop findings below it
+}
+
+// DeployConfig lists the deployment artifacts to synthesize
+type DeployConfig struct {
+	Artifacts []string `yaml:"artifacts" json:"artifacts,omitempty"`
+}
+
+// ValidationError lists every schema violation found in a config file
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", FileName, strings.Join(e.Problems, "; "))
+}
+
+// Read returns the raw config file at the root of a workspace, or nil when
+// the repository has none
+func Read(root string) ([]byte, error) {
+	f, err := os.Open(filepath.Join(root, FileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("%s exceeds %d bytes", FileName, maxFileSize)
+	}
+	return data, nil
+}
+
+// Parse decodes YAML config and validates it against the schema. Schema
+// violations are returned as a *ValidationError.
+func Parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, &ValidationError{Problems: []string{err.Error()}}
+	}
+	if cfg.Version == 0 {
+		cfg.Version = 1
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the config against the schema
+func (c *Config) Validate() error {
+	var problems []string
+	if c.Version != 1 {
+		problems = append(problems, fmt.Sprintf("unsupported version %d", c.Version))
+	}
+	for _, p := range c.Ignore {
+		if _, err := path.Match(strings.TrimSuffix(p, "/"), ""); err != nil || p == "" {
+			problems = append(problems, fmt.Sprintf("ignore: bad pattern %q", p))
+		}
+	}
+	if c.Parser.Language != "" {
+		if lang, ok := lookup(Languages, c.Parser.Language); ok {
+			c.Parser.Language = lang
+		} else {
+			problems = append(problems, fmt.Sprintf("parser.language: unknown language %q", c.Parser.Language))
+		}
+	}
+	for i, rs := range c.Scanner.RuleSets {
+		if v, ok := lookup(RuleSets, rs); ok {
+			c.Scanner.RuleSets[i] = v
+		} else {
+			problems = append(problems, fmt.Sprintf("scanner.rulesets: unknown rule set %q", rs))
+		}
+	}
+	if t := c.Scanner.SeverityThreshold; t != "" {
+		if v, ok := lookup(Severities, t); ok {
+			c.Scanner.SeverityThreshold = v
+		} else {
+			problems = append(problems, fmt.Sprintf("scanner.severity_threshold: must be one of %v", Severities))
+		}
+	}
+	for i, a := range c.Deploy.Artifacts {
+		if v, ok := lookup(Artifacts, a); ok {
+			c.Deploy.Artifacts[i] = v
+		} else {
+			problems = append(problems, fmt.Sprintf("deploy.artifacts: unknown artifact %q", a))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// Encode serializes a parsed config for passing to other stages
+func (c *Config) Encode() string {
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+// Decode reads a config produced by Encode. An empty string yields the
+// defaults.
+func Decode(s string) (*Config, error) {
+	cfg := &Config{Version: 1}
+	if s == "" {
+		return cfg, nil
+	}
+	if err := json.Unmarshal([]byte(s), cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode repository config: %w", err)
+	}
+	return cfg, nil
+}
+
+// IgnorePaths adds exact paths, relative to the repository root, to the
+// ignore list
+func (c *Config) IgnorePaths(paths ...string) {
+	for _, p := range paths {
+		c.Ignore = append(c.Ignore, "/"+globEscaper.Replace(filepath.ToSlash(p)))
+	}
+}
+
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+
+// Ignored reports whether a slash-separated path relative to the repository
+// root matches one of the ignore patterns. Patterns without a slash match
+// any path element; a leading slash anchors a pattern at the root; a
+// trailing slash matches a directory and its contents.
+func (c *Config) Ignored(rel string) bool {
+	rel = strings.TrimPrefix(filepath.ToSlash(rel), "./")
+	parts := strings.Split(rel, "/")
+	for _, p := range c.Ignore {
+		anchored := strings.HasPrefix(p, "/")
+		p = strings.TrimPrefix(p, "/")
+		p = strings.TrimSuffix(p, "/")
+		if anchored || strings.Contains(p, "/") {
+			// Anchored: match the path or any of its parent directories
+			for i := len(parts); i > 0; i-- {
+				if ok, _ := path.Match(p, strings.Join(parts[:i], "/")); ok {
+					return true
+				}
+			}
+			continue
+		}
+		for _, part := range parts {
+			if ok, _ := path.Match(p, part); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RuleSetEnabled reports whether the scanner should run a rule set
+func (c *Config) RuleSetEnabled(name string) bool {
+	if len(c.Scanner.RuleSets) == 0 {
+		return true
+	}
+	_, ok := lookup(c.Scanner.RuleSets, name)
+	return ok
+}
+
+// MeetsThreshold reports whether a finding of the given severity should be
+// reported
+func (c *Config) MeetsThreshold(severity string) bool {
+	if c.Scanner.SeverityThreshold == "" {
+		return true
+	}
+	return rank(severity) >= rank(c.Scanner.SeverityThreshold)
+}
+
+func rank(severity string) int {
+	for i, s := range Severities {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lookup finds v in list case-insensitively and returns the canonical spelling
+func lookup(list []string, v string) (string, bool) {
+	for _, item := range list {
+		if strings.EqualFold(item, v) {
+			return item, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,26 @@
+package utils
+
+import "net/url"
+
+// RedactURL hides credentials embedded in a URL so it can be logged. Values
+// that do not parse as URLs are returned unchanged.
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	u.User = url.User("***")
+	return u.String()
+}
+
+// StripCredentials removes the user information from a URL so it can be
+// recorded. scp-like git addresses such as git@host:repo have no password
+// and are returned unchanged, as are values that do not parse as URLs.
+func StripCredentials(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: admin.proto
+
+package adminpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_PauseIntake_FullMethodName     = "/adminpb.AdminService/PauseIntake"
+	AdminService_ResumeIntake_FullMethodName    = "/adminpb.AdminService/ResumeIntake"
+	AdminService_GetIntakeStatus_FullMethodName = "/adminpb.AdminService/GetIntakeStatus"
+	AdminService_QueryAudit_FullMethodName      = "/adminpb.AdminService/QueryAudit"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// --- Admin Service ---
+// Operational controls exposed by every Unarya service
+type AdminServiceClient interface {
+	// Stop accepting new jobs; jobs already running keep going
+	PauseIntake(ctx context.Context, in *IntakeRequest, opts ...grpc.CallOption) (*IntakeStatus, error)
+	// Start accepting new jobs again
+	ResumeIntake(ctx context.Context, in *IntakeRequest, opts ...grpc.CallOption) (*IntakeStatus, error)
+	// Report whether intake is open and how many jobs are in flight
+	GetIntakeStatus(ctx context.Context, in *IntakeRequest, opts ...grpc.CallOption) (*IntakeStatus, error)
+	// Read the service's audit log, filtered by subject and time range
+	QueryAudit(ctx context.Context, in *AuditQuery, opts ...grpc.CallOption) (*AuditRecords, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) PauseIntake(ctx context.Context, in *IntakeRequest, opts ...grpc.CallOption) (*IntakeStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IntakeStatus)
+	err := c.cc.Invoke(ctx, AdminService_PauseIntake_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ResumeIntake(ctx context.Context, in *IntakeRequest, opts ...grpc.CallOption) (*IntakeStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IntakeStatus)
+	err := c.cc.Invoke(ctx, AdminService_ResumeIntake_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetIntakeStatus(ctx context.Context, in *IntakeRequest, opts ...grpc.CallOption) (*IntakeStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IntakeStatus)
+	err := c.cc.Invoke(ctx, AdminService_GetIntakeStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) QueryAudit(ctx context.Context, in *AuditQuery, opts ...grpc.CallOption) (*AuditRecords, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditRecords)
+	err := c.cc.Invoke(ctx, AdminService_QueryAudit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// --- Admin Service ---
+// Operational controls exposed by every Unarya service
+type AdminServiceServer interface {
+	// Stop accepting new jobs; jobs already running keep going
+	PauseIntake(context.Context, *IntakeRequest) (*IntakeStatus, error)
+	// Start accepting new jobs again
+	ResumeIntake(context.Context, *IntakeRequest) (*IntakeStatus, error)
+	// Report whether intake is open and how many jobs are in flight
+	GetIntakeStatus(context.Context, *IntakeRequest) (*IntakeStatus, error)
+	// Read the service's audit log, filtered by subject and time range
+	QueryAudit(context.Context, *AuditQuery) (*AuditRecords, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) PauseIntake(context.Context, *IntakeRequest) (*IntakeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseIntake not implemented")
+}
+func (UnimplementedAdminServiceServer) ResumeIntake(context.Context, *IntakeRequest) (*IntakeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeIntake not implemented")
+}
+func (UnimplementedAdminServiceServer) GetIntakeStatus(context.Context, *IntakeRequest) (*IntakeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIntakeStatus not implemented")
+}
+func (UnimplementedAdminServiceServer) QueryAudit(context.Context, *AuditQuery) (*AuditRecords, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAudit not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_PauseIntake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).PauseIntake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_PauseIntake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).PauseIntake(ctx, req.(*IntakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ResumeIntake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ResumeIntake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ResumeIntake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ResumeIntake(ctx, req.(*IntakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetIntakeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetIntakeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetIntakeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetIntakeStatus(ctx, req.(*IntakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_QueryAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).QueryAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_QueryAudit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).QueryAudit(ctx, req.(*AuditQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "adminpb.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PauseIntake",
+			Handler:    _AdminService_PauseIntake_Handler,
+		},
+		{
+			MethodName: "ResumeIntake",
+			Handler:    _AdminService_ResumeIntake_Handler,
+		},
+		{
+			MethodName: "GetIntakeStatus",
+			Handler:    _AdminService_GetIntakeStatus_Handler,
+		},
+		{
+			MethodName: "QueryAudit",
+			Handler:    _AdminService_QueryAudit_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
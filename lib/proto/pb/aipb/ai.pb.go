@@ -409,6 +409,7 @@ type AIAnalyzeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Language      string                 `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`                                // Programming language (e.g. "Python", "Go")
 	CodeStructure string                 `protobuf:"bytes,2,opt,name=code_structure,json=codeStructure,proto3" json:"code_structure,omitempty"` // Code structure, AST, or intermediate representation
+	RepoConfig    string                 `protobuf:"bytes,3,opt,name=repo_config,json=repoConfig,proto3" json:"repo_config,omitempty"`          // Validated .unarya.yml as JSON, empty for defaults
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -457,6 +458,13 @@ func (x *AIAnalyzeRequest) GetCodeStructure() string {
 	return ""
 }
 
+func (x *AIAnalyzeRequest) GetRepoConfig() string {
+	if x != nil {
+		return x.RepoConfig
+	}
+	return ""
+}
+
 type AIAnalyzeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Insights      string                 `protobuf:"bytes,1,opt,name=insights,proto3" json:"insights,omitempty"`     // Example: "Detected MVC pattern", "Possible logic flaw"
@@ -534,10 +542,12 @@ const file_ai_proto_rawDesc = "" +
 	"\x0eStatusResponse\x12\x14\n" +
 	"\x05model\x18\x01 \x01(\tR\x05model\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12\x14\n" +
-	"\x05state\x18\x03 \x01(\tR\x05state\"U\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\"v\n" +
 	"\x10AIAnalyzeRequest\x12\x1a\n" +
 	"\blanguage\x18\x01 \x01(\tR\blanguage\x12%\n" +
-	"\x0ecode_structure\x18\x02 \x01(\tR\rcodeStructure\"O\n" +
+	"\x0ecode_structure\x18\x02 \x01(\tR\rcodeStructure\x12\x1f\n" +
+	"\vrepo_config\x18\x03 \x01(\tR\n" +
+	"repoConfig\"O\n" +
 	"\x11AIAnalyzeResponse\x12\x1a\n" +
 	"\binsights\x18\x01 \x01(\tR\binsights\x12\x1e\n" +
 	"\n" +
@@ -0,0 +1,31 @@
+package collectorpb
+
+import "google.golang.org/protobuf/proto"
+
+// The audit interceptor records the repository and job ID of a call from
+// these, see AuditSource and AuditResult in internal/shared/grpc
+
+// AuditSource returns the chosen source, which holds the URL and job ID
+func (x *CollectRequest) AuditSource() proto.Message {
+	m := x.ProtoReflect()
+	if fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("source")); fd != nil {
+		return m.Get(fd).Message().Interface()
+	}
+	return nil
+}
+
+// AuditSource returns the upload's metadata, sent with the first chunk
+func (x *UploadChunk) AuditSource() proto.Message {
+	if md := x.GetMetadata(); md != nil {
+		return md
+	}
+	return nil
+}
+
+// AuditResult returns the collection's result; progress events have none
+func (x *CollectEvent) AuditResult() proto.Message {
+	if r := x.GetResult(); r != nil {
+		return r
+	}
+	return nil
+}
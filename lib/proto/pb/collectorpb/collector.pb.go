@@ -26,6 +26,18 @@ type GitRequest struct {
 	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
 	Branch        string                 `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
 	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	Commit        string                 `protobuf:"bytes,4,opt,name=commit,proto3" json:"commit,omitempty"`                                       // Full commit SHA to check out instead of a branch
+	Tag           string                 `protobuf:"bytes,5,opt,name=tag,proto3" json:"tag,omitempty"`                                             // Tag to check out instead of a branch
+	Subpath       string                 `protobuf:"bytes,6,opt,name=subpath,proto3" json:"subpath,omitempty"`                                     // Sparse-checkout this directory only
+	Depth         int32                  `protobuf:"varint,7,opt,name=depth,proto3" json:"depth,omitempty"`                                        // History depth; 0 for the latest commit, -1 for full history
+	Submodules    bool                   `protobuf:"varint,8,opt,name=submodules,proto3" json:"submodules,omitempty"`                              // Check out submodules recursively
+	Lfs           bool                   `protobuf:"varint,9,opt,name=lfs,proto3" json:"lfs,omitempty"`                                            // Fetch Git LFS objects instead of leaving pointer files
+	SshPrivateKey string                 `protobuf:"bytes,10,opt,name=ssh_private_key,json=sshPrivateKey,proto3" json:"ssh_private_key,omitempty"` // Deploy key for git@ and ssh:// URLs
+	SshKnownHosts string                 `protobuf:"bytes,11,opt,name=ssh_known_hosts,json=sshKnownHosts,proto3" json:"ssh_known_hosts,omitempty"` // known_hosts lines the server must match
+	SshKeyRef     string                 `protobuf:"bytes,12,opt,name=ssh_key_ref,json=sshKeyRef,proto3" json:"ssh_key_ref,omitempty"`             // Name of a key stored on the collector, instead of ssh_private_key
+	JobId         string                 `protobuf:"bytes,13,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`                           // Job that holds a reference to the workspace
+	BaseRef       string                 `protobuf:"bytes,14,opt,name=base_ref,json=baseRef,proto3" json:"base_ref,omitempty"`                     // Pull request base: list changes since the merge base with it
+	HistoryDepth  int32                  `protobuf:"varint,15,opt,name=history_depth,json=historyDepth,proto3" json:"history_depth,omitempty"`     // Mine up to this many commits for per-file churn; 0 disables
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -81,28 +93,1870 @@ func (x *GitRequest) GetToken() string {
 	return ""
 }
 
+func (x *GitRequest) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *GitRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *GitRequest) GetSubpath() string {
+	if x != nil {
+		return x.Subpath
+	}
+	return ""
+}
+
+func (x *GitRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *GitRequest) GetSubmodules() bool {
+	if x != nil {
+		return x.Submodules
+	}
+	return false
+}
+
+func (x *GitRequest) GetLfs() bool {
+	if x != nil {
+		return x.Lfs
+	}
+	return false
+}
+
+func (x *GitRequest) GetSshPrivateKey() string {
+	if x != nil {
+		return x.SshPrivateKey
+	}
+	return ""
+}
+
+func (x *GitRequest) GetSshKnownHosts() string {
+	if x != nil {
+		return x.SshKnownHosts
+	}
+	return ""
+}
+
+func (x *GitRequest) GetSshKeyRef() string {
+	if x != nil {
+		return x.SshKeyRef
+	}
+	return ""
+}
+
+func (x *GitRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *GitRequest) GetBaseRef() string {
+	if x != nil {
+		return x.BaseRef
+	}
+	return ""
+}
+
+func (x *GitRequest) GetHistoryDepth() int32 {
+	if x != nil {
+		return x.HistoryDepth
+	}
+	return 0
+}
+
 type ArchiveRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	NestedDepth   int32                  `protobuf:"varint,2,opt,name=nested_depth,json=nestedDepth,proto3" json:"nested_depth,omitempty"` // Levels of archives-within-archives to unpack; 0 disables
+	Sha256        string                 `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`                               // Expected checksum of the download, hex; optional
+	JobId         string                 `protobuf:"bytes,4,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`                    // Job that holds a reference to the workspace
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveRequest) Reset() {
+	*x = ArchiveRequest{}
+	mi := &file_collector_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveRequest) ProtoMessage() {}
+
+func (x *ArchiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ArchiveRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ArchiveRequest) GetNestedDepth() int32 {
+	if x != nil {
+		return x.NestedDepth
+	}
+	return 0
+}
+
+func (x *ArchiveRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ArchiveRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type URLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Sha256        string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`            // Expected checksum of the download, hex; optional
+	JobId         string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Job that holds a reference to the workspace
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLRequest) Reset() {
+	*x = URLRequest{}
+	mi := &file_collector_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLRequest) ProtoMessage() {}
+
+func (x *URLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLRequest.ProtoReflect.Descriptor instead.
+func (*URLRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *URLRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *URLRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *URLRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type LocalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`                // Absolute path or file:// URL below an allowed root
+	Snapshot      bool                   `protobuf:"varint,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`       // Copy into a workspace instead of reading in place
+	JobId         string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Job that holds a reference to the workspace
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocalRequest) Reset() {
+	*x = LocalRequest{}
+	mi := &file_collector_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocalRequest) ProtoMessage() {}
+
+func (x *LocalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocalRequest.ProtoReflect.Descriptor instead.
+func (*LocalRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LocalRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *LocalRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+func (x *LocalRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type PackageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ecosystem     string                 `protobuf:"bytes,1,opt,name=ecosystem,proto3" json:"ecosystem,omitempty"`      // "go", "npm" or "pypi"
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                // Module path, npm package (optionally @scope/name) or PyPI project
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`          // Exact version, e.g. v1.2.3 for Go
+	JobId         string                 `protobuf:"bytes,4,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Job that holds a reference to the workspace
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackageRequest) Reset() {
+	*x = PackageRequest{}
+	mi := &file_collector_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageRequest) ProtoMessage() {}
+
+func (x *PackageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageRequest.ProtoReflect.Descriptor instead.
+func (*PackageRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PackageRequest) GetEcosystem() string {
+	if x != nil {
+		return x.Ecosystem
+	}
+	return ""
+}
+
+func (x *PackageRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PackageRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *PackageRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type ImageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`                  // docker save or OCI layout tarball, optionally compressed
+	Sha256        string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`            // Expected checksum of the download, hex; optional
+	JobId         string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Job that holds a reference to the workspace
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageRequest) Reset() {
+	*x = ImageRequest{}
+	mi := &file_collector_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageRequest) ProtoMessage() {}
+
+func (x *ImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageRequest.ProtoReflect.Descriptor instead.
+func (*ImageRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ImageRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ImageRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ImageRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type CollectRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Source:
+	//
+	//	*CollectRequest_Git
+	//	*CollectRequest_Archive
+	//	*CollectRequest_Url
+	//	*CollectRequest_Local
+	//	*CollectRequest_Package
+	//	*CollectRequest_Image
+	Source        isCollectRequest_Source `protobuf_oneof:"source"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectRequest) Reset() {
+	*x = CollectRequest{}
+	mi := &file_collector_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectRequest) ProtoMessage() {}
+
+func (x *CollectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectRequest.ProtoReflect.Descriptor instead.
+func (*CollectRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CollectRequest) GetSource() isCollectRequest_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *CollectRequest) GetGit() *GitRequest {
+	if x != nil {
+		if x, ok := x.Source.(*CollectRequest_Git); ok {
+			return x.Git
+		}
+	}
+	return nil
+}
+
+func (x *CollectRequest) GetArchive() *ArchiveRequest {
+	if x != nil {
+		if x, ok := x.Source.(*CollectRequest_Archive); ok {
+			return x.Archive
+		}
+	}
+	return nil
+}
+
+func (x *CollectRequest) GetUrl() *URLRequest {
+	if x != nil {
+		if x, ok := x.Source.(*CollectRequest_Url); ok {
+			return x.Url
+		}
+	}
+	return nil
+}
+
+func (x *CollectRequest) GetLocal() *LocalRequest {
+	if x != nil {
+		if x, ok := x.Source.(*CollectRequest_Local); ok {
+			return x.Local
+		}
+	}
+	return nil
+}
+
+func (x *CollectRequest) GetPackage() *PackageRequest {
+	if x != nil {
+		if x, ok := x.Source.(*CollectRequest_Package); ok {
+			return x.Package
+		}
+	}
+	return nil
+}
+
+func (x *CollectRequest) GetImage() *ImageRequest {
+	if x != nil {
+		if x, ok := x.Source.(*CollectRequest_Image); ok {
+			return x.Image
+		}
+	}
+	return nil
+}
+
+type isCollectRequest_Source interface {
+	isCollectRequest_Source()
+}
+
+type CollectRequest_Git struct {
+	Git *GitRequest `protobuf:"bytes,1,opt,name=git,proto3,oneof"`
+}
+
+type CollectRequest_Archive struct {
+	Archive *ArchiveRequest `protobuf:"bytes,2,opt,name=archive,proto3,oneof"`
+}
+
+type CollectRequest_Url struct {
+	Url *URLRequest `protobuf:"bytes,3,opt,name=url,proto3,oneof"`
+}
+
+type CollectRequest_Local struct {
+	Local *LocalRequest `protobuf:"bytes,4,opt,name=local,proto3,oneof"`
+}
+
+type CollectRequest_Package struct {
+	Package *PackageRequest `protobuf:"bytes,5,opt,name=package,proto3,oneof"`
+}
+
+type CollectRequest_Image struct {
+	Image *ImageRequest `protobuf:"bytes,6,opt,name=image,proto3,oneof"`
+}
+
+func (*CollectRequest_Git) isCollectRequest_Source() {}
+
+func (*CollectRequest_Archive) isCollectRequest_Source() {}
+
+func (*CollectRequest_Url) isCollectRequest_Source() {}
+
+func (*CollectRequest_Local) isCollectRequest_Source() {}
+
+func (*CollectRequest_Package) isCollectRequest_Source() {}
+
+func (*CollectRequest_Image) isCollectRequest_Source() {}
+
+type CollectEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*CollectEvent_Progress
+	//	*CollectEvent_Result
+	Event         isCollectEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectEvent) Reset() {
+	*x = CollectEvent{}
+	mi := &file_collector_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectEvent) ProtoMessage() {}
+
+func (x *CollectEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectEvent.ProtoReflect.Descriptor instead.
+func (*CollectEvent) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CollectEvent) GetEvent() isCollectEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *CollectEvent) GetProgress() *CollectProgress {
+	if x != nil {
+		if x, ok := x.Event.(*CollectEvent_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *CollectEvent) GetResult() *CollectorResponse {
+	if x != nil {
+		if x, ok := x.Event.(*CollectEvent_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isCollectEvent_Event interface {
+	isCollectEvent_Event()
+}
+
+type CollectEvent_Progress struct {
+	Progress *CollectProgress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type CollectEvent_Result struct {
+	Result *CollectorResponse `protobuf:"bytes,2,opt,name=result,proto3,oneof"` // Always the last event
+}
+
+func (*CollectEvent_Progress) isCollectEvent_Event() {}
+
+func (*CollectEvent_Result) isCollectEvent_Event() {}
+
+type CollectProgress struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Stage          string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`                                            // git, download, extract or scan
+	Step           string                 `protobuf:"bytes,2,opt,name=step,proto3" json:"step,omitempty"`                                              // e.g. git's "Receiving objects" or "layer 2/5" of an image
+	Percent        int32                  `protobuf:"varint,3,opt,name=percent,proto3" json:"percent,omitempty"`                                       // -1 when the total is unknown
+	Done           int64                  `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`                                             // Objects, bytes, entries or files so far
+	Total          int64                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`                                           // 0 when unknown
+	BytesPerSecond int64                  `protobuf:"varint,6,opt,name=bytes_per_second,json=bytesPerSecond,proto3" json:"bytes_per_second,omitempty"` // Downloads and git transfers
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CollectProgress) Reset() {
+	*x = CollectProgress{}
+	mi := &file_collector_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectProgress) ProtoMessage() {}
+
+func (x *CollectProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectProgress.ProtoReflect.Descriptor instead.
+func (*CollectProgress) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CollectProgress) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *CollectProgress) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *CollectProgress) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *CollectProgress) GetDone() int64 {
+	if x != nil {
+		return x.Done
+	}
+	return 0
+}
+
+func (x *CollectProgress) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *CollectProgress) GetBytesPerSecond() int64 {
+	if x != nil {
+		return x.BytesPerSecond
+	}
+	return 0
+}
+
+type UploadMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`                               // e.g. "zip", "tar.gz"; detected from content when empty
+	Sha256        string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`                               // Hex SHA-256 of the complete archive
+	NestedDepth   int32                  `protobuf:"varint,3,opt,name=nested_depth,json=nestedDepth,proto3" json:"nested_depth,omitempty"` // Levels of archives-within-archives to unpack; 0 disables
+	JobId         string                 `protobuf:"bytes,4,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`                    // Job that holds a reference to the workspace
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadMetadata) Reset() {
+	*x = UploadMetadata{}
+	mi := &file_collector_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMetadata) ProtoMessage() {}
+
+func (x *UploadMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMetadata.ProtoReflect.Descriptor instead.
+func (*UploadMetadata) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UploadMetadata) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *UploadMetadata) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *UploadMetadata) GetNestedDepth() int32 {
+	if x != nil {
+		return x.NestedDepth
+	}
+	return 0
+}
+
+func (x *UploadMetadata) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type UploadChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *UploadMetadata        `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"` // Required on the first message only
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadChunk) Reset() {
+	*x = UploadChunk{}
+	mi := &file_collector_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadChunk) ProtoMessage() {}
+
+func (x *UploadChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadChunk.ProtoReflect.Descriptor instead.
+func (*UploadChunk) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UploadChunk) GetMetadata() *UploadMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UploadChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ValidateRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Url              string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Type             string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // "git" (default), "archive", "url", "local", "package", "image"
+	Branch           string                 `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	Token            string                 `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	Commit           string                 `protobuf:"bytes,5,opt,name=commit,proto3" json:"commit,omitempty"`
+	Tag              string                 `protobuf:"bytes,6,opt,name=tag,proto3" json:"tag,omitempty"`
+	SshPrivateKey    string                 `protobuf:"bytes,7,opt,name=ssh_private_key,json=sshPrivateKey,proto3" json:"ssh_private_key,omitempty"`
+	SshKnownHosts    string                 `protobuf:"bytes,8,opt,name=ssh_known_hosts,json=sshKnownHosts,proto3" json:"ssh_known_hosts,omitempty"`
+	SshKeyRef        string                 `protobuf:"bytes,9,opt,name=ssh_key_ref,json=sshKeyRef,proto3" json:"ssh_key_ref,omitempty"`
+	PackageEcosystem string                 `protobuf:"bytes,10,opt,name=package_ecosystem,json=packageEcosystem,proto3" json:"package_ecosystem,omitempty"` // Package sources
+	PackageName      string                 `protobuf:"bytes,11,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
+	PackageVersion   string                 `protobuf:"bytes,12,opt,name=package_version,json=packageVersion,proto3" json:"package_version,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	mi := &file_collector_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ValidateRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetSshPrivateKey() string {
+	if x != nil {
+		return x.SshPrivateKey
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetSshKnownHosts() string {
+	if x != nil {
+		return x.SshKnownHosts
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetSshKeyRef() string {
+	if x != nil {
+		return x.SshKeyRef
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetPackageEcosystem() string {
+	if x != nil {
+		return x.PackageEcosystem
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetPackageVersion() string {
+	if x != nil {
+		return x.PackageVersion
+	}
+	return ""
+}
+
+type ValidateResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Valid              bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Message            string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ResolvedCommit     string                 `protobuf:"bytes,3,opt,name=resolved_commit,json=resolvedCommit,proto3" json:"resolved_commit,omitempty"`                // Commit the branch points to (git only)
+	EstimatedSizeBytes int64                  `protobuf:"varint,4,opt,name=estimated_size_bytes,json=estimatedSizeBytes,proto3" json:"estimated_size_bytes,omitempty"` // 0 when the size is unknown
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_collector_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ValidateResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ValidateResponse) GetResolvedCommit() string {
+	if x != nil {
+		return x.ResolvedCommit
+	}
+	return ""
+}
+
+func (x *ValidateResponse) GetEstimatedSizeBytes() int64 {
+	if x != nil {
+		return x.EstimatedSizeBytes
+	}
+	return 0
+}
+
+type CollectorResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Message        string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Path           string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	RepoConfig     string                 `protobuf:"bytes,3,opt,name=repo_config,json=repoConfig,proto3" json:"repo_config,omitempty"`                                                        // Raw .unarya.yml found at the workspace root, if any
+	ResolvedCommit string                 `protobuf:"bytes,4,opt,name=resolved_commit,json=resolvedCommit,proto3" json:"resolved_commit,omitempty"`                                            // Commit that was checked out (git only)
+	LfsPointers    []string               `protobuf:"bytes,5,rep,name=lfs_pointers,json=lfsPointers,proto3" json:"lfs_pointers,omitempty"`                                                     // Git LFS pointer files left unfetched, relative to path
+	WorkspaceId    string                 `protobuf:"bytes,6,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`                                                     // Release with ReleaseWorkspace when done
+	ArtifactDigest string                 `protobuf:"bytes,7,opt,name=artifact_digest,json=artifactDigest,proto3" json:"artifact_digest,omitempty"`                                            // Snapshot of path in the artifact store, when one is configured
+	Languages      map[string]int64       `protobuf:"bytes,8,rep,name=languages,proto3" json:"languages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Bytes of source and test files per language
+	Manifest       []*ManifestEntry       `protobuf:"bytes,9,rep,name=manifest,proto3" json:"manifest,omitempty"`                                                                              // Every collected file, sorted by path
+	Provenance     *Provenance            `protobuf:"bytes,10,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	MerkleRoot     string                 `protobuf:"bytes,11,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"` // sha256:<hex> Merkle root over the manifest (RFC 6962 layout)
+	Changes        *ChangeSet             `protobuf:"bytes,12,opt,name=changes,proto3" json:"changes,omitempty"`                         // Set for git requests with a base_ref
+	History        *History               `protobuf:"bytes,13,opt,name=history,proto3" json:"history,omitempty"`                         // Set for git requests with a history_depth
+	Image          *ImageInfo             `protobuf:"bytes,14,opt,name=image,proto3" json:"image,omitempty"`                             // Set for image requests; path holds the flattened rootfs
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CollectorResponse) Reset() {
+	*x = CollectorResponse{}
+	mi := &file_collector_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectorResponse) ProtoMessage() {}
+
+func (x *CollectorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectorResponse.ProtoReflect.Descriptor instead.
+func (*CollectorResponse) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CollectorResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CollectorResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *CollectorResponse) GetRepoConfig() string {
+	if x != nil {
+		return x.RepoConfig
+	}
+	return ""
+}
+
+func (x *CollectorResponse) GetResolvedCommit() string {
+	if x != nil {
+		return x.ResolvedCommit
+	}
+	return ""
+}
+
+func (x *CollectorResponse) GetLfsPointers() []string {
+	if x != nil {
+		return x.LfsPointers
+	}
+	return nil
+}
+
+func (x *CollectorResponse) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *CollectorResponse) GetArtifactDigest() string {
+	if x != nil {
+		return x.ArtifactDigest
+	}
+	return ""
+}
+
+func (x *CollectorResponse) GetLanguages() map[string]int64 {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *CollectorResponse) GetManifest() []*ManifestEntry {
+	if x != nil {
+		return x.Manifest
+	}
+	return nil
+}
+
+func (x *CollectorResponse) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *CollectorResponse) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *CollectorResponse) GetChanges() *ChangeSet {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *CollectorResponse) GetHistory() *History {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *CollectorResponse) GetImage() *ImageInfo {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+type ImageInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RepoTags      []string               `protobuf:"bytes,1,rep,name=repo_tags,json=repoTags,proto3" json:"repo_tags,omitempty"`
+	Os            string                 `protobuf:"bytes,2,opt,name=os,proto3" json:"os,omitempty"`
+	Architecture  string                 `protobuf:"bytes,3,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	Entrypoint    []string               `protobuf:"bytes,4,rep,name=entrypoint,proto3" json:"entrypoint,omitempty"`
+	Cmd           []string               `protobuf:"bytes,5,rep,name=cmd,proto3" json:"cmd,omitempty"`
+	Env           []string               `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty"`
+	ExposedPorts  []string               `protobuf:"bytes,7,rep,name=exposed_ports,json=exposedPorts,proto3" json:"exposed_ports,omitempty"` // e.g. "8080/tcp"
+	User          string                 `protobuf:"bytes,8,opt,name=user,proto3" json:"user,omitempty"`                                     // Empty means root
+	WorkingDir    string                 `protobuf:"bytes,9,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Layers        int32                  `protobuf:"varint,11,opt,name=layers,proto3" json:"layers,omitempty"`
+	Packages      []*OSPackage           `protobuf:"bytes,12,rep,name=packages,proto3" json:"packages,omitempty"`
+	Warnings      []string               `protobuf:"bytes,13,rep,name=warnings,proto3" json:"warnings,omitempty"` // Package databases that could not be read
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageInfo) Reset() {
+	*x = ImageInfo{}
+	mi := &file_collector_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageInfo) ProtoMessage() {}
+
+func (x *ImageInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageInfo.ProtoReflect.Descriptor instead.
+func (*ImageInfo) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ImageInfo) GetRepoTags() []string {
+	if x != nil {
+		return x.RepoTags
+	}
+	return nil
+}
+
+func (x *ImageInfo) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *ImageInfo) GetArchitecture() string {
+	if x != nil {
+		return x.Architecture
+	}
+	return ""
+}
+
+func (x *ImageInfo) GetEntrypoint() []string {
+	if x != nil {
+		return x.Entrypoint
+	}
+	return nil
+}
+
+func (x *ImageInfo) GetCmd() []string {
+	if x != nil {
+		return x.Cmd
+	}
+	return nil
+}
+
+func (x *ImageInfo) GetEnv() []string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ImageInfo) GetExposedPorts() []string {
+	if x != nil {
+		return x.ExposedPorts
+	}
+	return nil
+}
+
+func (x *ImageInfo) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ImageInfo) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *ImageInfo) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ImageInfo) GetLayers() int32 {
+	if x != nil {
+		return x.Layers
+	}
+	return 0
+}
+
+func (x *ImageInfo) GetPackages() []*OSPackage {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+func (x *ImageInfo) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type OSPackage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Manager       string                 `protobuf:"bytes,1,opt,name=manager,proto3" json:"manager,omitempty"` // dpkg, apk or rpm
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Architecture  string                 `protobuf:"bytes,4,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"` // Source package, or apk origin, when it differs from name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OSPackage) Reset() {
+	*x = OSPackage{}
+	mi := &file_collector_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OSPackage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OSPackage) ProtoMessage() {}
+
+func (x *OSPackage) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OSPackage.ProtoReflect.Descriptor instead.
+func (*OSPackage) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *OSPackage) GetManager() string {
+	if x != nil {
+		return x.Manager
+	}
+	return ""
+}
+
+func (x *OSPackage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OSPackage) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *OSPackage) GetArchitecture() string {
+	if x != nil {
+		return x.Architecture
+	}
+	return ""
+}
+
+func (x *OSPackage) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type History struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commits       int32                  `protobuf:"varint,1,opt,name=commits,proto3" json:"commits,omitempty"` // Commits walked, merges excluded
+	Files         []*FileHistory         `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *History) Reset() {
+	*x = History{}
+	mi := &file_collector_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *History) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*History) ProtoMessage() {}
+
+func (x *History) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use History.ProtoReflect.Descriptor instead.
+func (*History) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *History) GetCommits() int32 {
+	if x != nil {
+		return x.Commits
+	}
+	return 0
+}
+
+func (x *History) GetFiles() []*FileHistory {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// FileHistory joins with ManifestEntry and the parser's metrics on path
+type FileHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Commits       int32                  `protobuf:"varint,2,opt,name=commits,proto3" json:"commits,omitempty"`
+	LinesAdded    int64                  `protobuf:"varint,3,opt,name=lines_added,json=linesAdded,proto3" json:"lines_added,omitempty"`
+	LinesRemoved  int64                  `protobuf:"varint,4,opt,name=lines_removed,json=linesRemoved,proto3" json:"lines_removed,omitempty"`
+	Authors       int32                  `protobuf:"varint,5,opt,name=authors,proto3" json:"authors,omitempty"`                              // Distinct author emails
+	LastModified  string                 `protobuf:"bytes,6,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"` // RFC 3339
+	LastCommit    string                 `protobuf:"bytes,7,opt,name=last_commit,json=lastCommit,proto3" json:"last_commit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileHistory) Reset() {
+	*x = FileHistory{}
+	mi := &file_collector_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileHistory) ProtoMessage() {}
+
+func (x *FileHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileHistory.ProtoReflect.Descriptor instead.
+func (*FileHistory) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *FileHistory) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileHistory) GetCommits() int32 {
+	if x != nil {
+		return x.Commits
+	}
+	return 0
+}
+
+func (x *FileHistory) GetLinesAdded() int64 {
+	if x != nil {
+		return x.LinesAdded
+	}
+	return 0
+}
+
+func (x *FileHistory) GetLinesRemoved() int64 {
+	if x != nil {
+		return x.LinesRemoved
+	}
+	return 0
+}
+
+func (x *FileHistory) GetAuthors() int32 {
+	if x != nil {
+		return x.Authors
+	}
+	return 0
+}
+
+func (x *FileHistory) GetLastModified() string {
+	if x != nil {
+		return x.LastModified
+	}
+	return ""
+}
+
+func (x *FileHistory) GetLastCommit() string {
+	if x != nil {
+		return x.LastCommit
+	}
+	return ""
+}
+
+type ChangeSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseRef       string                 `protobuf:"bytes,1,opt,name=base_ref,json=baseRef,proto3" json:"base_ref,omitempty"`
+	BaseCommit    string                 `protobuf:"bytes,2,opt,name=base_commit,json=baseCommit,proto3" json:"base_commit,omitempty"`
+	HeadCommit    string                 `protobuf:"bytes,3,opt,name=head_commit,json=headCommit,proto3" json:"head_commit,omitempty"`
+	MergeBase     string                 `protobuf:"bytes,4,opt,name=merge_base,json=mergeBase,proto3" json:"merge_base,omitempty"`
+	Files         []*ChangedFile         `protobuf:"bytes,5,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeSet) Reset() {
+	*x = ChangeSet{}
+	mi := &file_collector_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeSet) ProtoMessage() {}
+
+func (x *ChangeSet) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeSet.ProtoReflect.Descriptor instead.
+func (*ChangeSet) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ChangeSet) GetBaseRef() string {
+	if x != nil {
+		return x.BaseRef
+	}
+	return ""
+}
+
+func (x *ChangeSet) GetBaseCommit() string {
+	if x != nil {
+		return x.BaseCommit
+	}
+	return ""
+}
+
+func (x *ChangeSet) GetHeadCommit() string {
+	if x != nil {
+		return x.HeadCommit
+	}
+	return ""
+}
+
+func (x *ChangeSet) GetMergeBase() string {
+	if x != nil {
+		return x.MergeBase
+	}
+	return ""
+}
+
+func (x *ChangeSet) GetFiles() []*ChangedFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type ChangedFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`                      // Relative to the response's path; the old path for deleted files
+	OldPath       string                 `protobuf:"bytes,2,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"` // Renamed and copied files
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                  // added, modified, deleted, renamed or copied
+	Binary        bool                   `protobuf:"varint,4,opt,name=binary,proto3" json:"binary,omitempty"`
+	Hunks         []*Hunk                `protobuf:"bytes,5,rep,name=hunks,proto3" json:"hunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangedFile) Reset() {
+	*x = ChangedFile{}
+	mi := &file_collector_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangedFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangedFile) ProtoMessage() {}
+
+func (x *ChangedFile) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangedFile.ProtoReflect.Descriptor instead.
+func (*ChangedFile) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ChangedFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ChangedFile) GetOldPath() string {
+	if x != nil {
+		return x.OldPath
+	}
+	return ""
+}
+
+func (x *ChangedFile) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ChangedFile) GetBinary() bool {
+	if x != nil {
+		return x.Binary
+	}
+	return false
+}
+
+func (x *ChangedFile) GetHunks() []*Hunk {
+	if x != nil {
+		return x.Hunks
+	}
+	return nil
+}
+
+type Hunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldStart      int32                  `protobuf:"varint,1,opt,name=old_start,json=oldStart,proto3" json:"old_start,omitempty"`
+	OldLines      int32                  `protobuf:"varint,2,opt,name=old_lines,json=oldLines,proto3" json:"old_lines,omitempty"`
+	NewStart      int32                  `protobuf:"varint,3,opt,name=new_start,json=newStart,proto3" json:"new_start,omitempty"`
+	NewLines      int32                  `protobuf:"varint,4,opt,name=new_lines,json=newLines,proto3" json:"new_lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hunk) Reset() {
+	*x = Hunk{}
+	mi := &file_collector_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hunk) ProtoMessage() {}
+
+func (x *Hunk) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hunk.ProtoReflect.Descriptor instead.
+func (*Hunk) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Hunk) GetOldStart() int32 {
+	if x != nil {
+		return x.OldStart
+	}
+	return 0
+}
+
+func (x *Hunk) GetOldLines() int32 {
+	if x != nil {
+		return x.OldLines
+	}
+	return 0
+}
+
+func (x *Hunk) GetNewStart() int32 {
+	if x != nil {
+		return x.NewStart
+	}
+	return 0
+}
+
+func (x *Hunk) GetNewLines() int32 {
+	if x != nil {
+		return x.NewLines
+	}
+	return 0
+}
+
+type ManifestEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to the response's path, slash-separated
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        string                 `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Class         string                 `protobuf:"bytes,4,opt,name=class,proto3" json:"class,omitempty"` // source, test, generated, vendored, binary, documentation, config or other
+	Language      string                 `protobuf:"bytes,5,opt,name=language,proto3" json:"language,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ManifestEntry) Reset() {
+	*x = ManifestEntry{}
+	mi := &file_collector_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManifestEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManifestEntry) ProtoMessage() {}
+
+func (x *ManifestEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManifestEntry.ProtoReflect.Descriptor instead.
+func (*ManifestEntry) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ManifestEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ManifestEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ManifestEntry) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ManifestEntry) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *ManifestEntry) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+type Provenance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SourceType    string                 `protobuf:"bytes,1,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`                                 // Credentials stripped; empty for local sources
+	Commit        string                 `protobuf:"bytes,3,opt,name=commit,proto3" json:"commit,omitempty"`                           // Git only
+	Author        string                 `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`                           // Git only, "Name <email>"
+	CommitTime    string                 `protobuf:"bytes,5,opt,name=commit_time,json=commitTime,proto3" json:"commit_time,omitempty"` // Git only, RFC 3339
+	FetchedAt     string                 `protobuf:"bytes,6,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"`    // RFC 3339
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ArchiveRequest) Reset() {
-	*x = ArchiveRequest{}
-	mi := &file_collector_proto_msgTypes[1]
+func (x *Provenance) Reset() {
+	*x = Provenance{}
+	mi := &file_collector_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ArchiveRequest) String() string {
+func (x *Provenance) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ArchiveRequest) ProtoMessage() {}
+func (*Provenance) ProtoMessage() {}
 
-func (x *ArchiveRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_collector_proto_msgTypes[1]
+func (x *Provenance) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -113,40 +1967,76 @@ func (x *ArchiveRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ArchiveRequest.ProtoReflect.Descriptor instead.
-func (*ArchiveRequest) Descriptor() ([]byte, []int) {
-	return file_collector_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use Provenance.ProtoReflect.Descriptor instead.
+func (*Provenance) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ArchiveRequest) GetUrl() string {
+func (x *Provenance) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *Provenance) GetUrl() string {
 	if x != nil {
 		return x.Url
 	}
 	return ""
 }
 
-type URLRequest struct {
+func (x *Provenance) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *Provenance) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Provenance) GetCommitTime() string {
+	if x != nil {
+		return x.CommitTime
+	}
+	return ""
+}
+
+func (x *Provenance) GetFetchedAt() string {
+	if x != nil {
+		return x.FetchedAt
+	}
+	return ""
+}
+
+type AcquireWorkspaceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	JobId         string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Reference to add; release it with ReleaseWorkspace
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *URLRequest) Reset() {
-	*x = URLRequest{}
-	mi := &file_collector_proto_msgTypes[2]
+func (x *AcquireWorkspaceRequest) Reset() {
+	*x = AcquireWorkspaceRequest{}
+	mi := &file_collector_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *URLRequest) String() string {
+func (x *AcquireWorkspaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*URLRequest) ProtoMessage() {}
+func (*AcquireWorkspaceRequest) ProtoMessage() {}
 
-func (x *URLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_collector_proto_msgTypes[2]
+func (x *AcquireWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -157,40 +2047,48 @@ func (x *URLRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use URLRequest.ProtoReflect.Descriptor instead.
-func (*URLRequest) Descriptor() ([]byte, []int) {
-	return file_collector_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use AcquireWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*AcquireWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *URLRequest) GetUrl() string {
+func (x *AcquireWorkspaceRequest) GetWorkspaceId() string {
 	if x != nil {
-		return x.Url
+		return x.WorkspaceId
 	}
 	return ""
 }
 
-type ValidateRequest struct {
+func (x *AcquireWorkspaceRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type ReleaseWorkspaceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	JobId         string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Reference to drop; required
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ValidateRequest) Reset() {
-	*x = ValidateRequest{}
-	mi := &file_collector_proto_msgTypes[3]
+func (x *ReleaseWorkspaceRequest) Reset() {
+	*x = ReleaseWorkspaceRequest{}
+	mi := &file_collector_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateRequest) String() string {
+func (x *ReleaseWorkspaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateRequest) ProtoMessage() {}
+func (*ReleaseWorkspaceRequest) ProtoMessage() {}
 
-func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_collector_proto_msgTypes[3]
+func (x *ReleaseWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -201,41 +2099,48 @@ func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
-func (*ValidateRequest) Descriptor() ([]byte, []int) {
-	return file_collector_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ReleaseWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *ValidateRequest) GetUrl() string {
+func (x *ReleaseWorkspaceRequest) GetWorkspaceId() string {
 	if x != nil {
-		return x.Url
+		return x.WorkspaceId
 	}
 	return ""
 }
 
-type ValidateResponse struct {
+func (x *ReleaseWorkspaceRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type ReleaseWorkspaceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Removed       bool                   `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"` // The workspace was deleted
+	RemainingRefs int32                  `protobuf:"varint,2,opt,name=remaining_refs,json=remainingRefs,proto3" json:"remaining_refs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ValidateResponse) Reset() {
-	*x = ValidateResponse{}
-	mi := &file_collector_proto_msgTypes[4]
+func (x *ReleaseWorkspaceResponse) Reset() {
+	*x = ReleaseWorkspaceResponse{}
+	mi := &file_collector_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateResponse) String() string {
+func (x *ReleaseWorkspaceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateResponse) ProtoMessage() {}
+func (*ReleaseWorkspaceResponse) ProtoMessage() {}
 
-func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_collector_proto_msgTypes[4]
+func (x *ReleaseWorkspaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -246,48 +2151,100 @@ func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
-func (*ValidateResponse) Descriptor() ([]byte, []int) {
-	return file_collector_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ReleaseWorkspaceResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *ValidateResponse) GetValid() bool {
+func (x *ReleaseWorkspaceResponse) GetRemoved() bool {
 	if x != nil {
-		return x.Valid
+		return x.Removed
 	}
 	return false
 }
 
-func (x *ValidateResponse) GetMessage() string {
+func (x *ReleaseWorkspaceResponse) GetRemainingRefs() int32 {
 	if x != nil {
-		return x.Message
+		return x.RemainingRefs
+	}
+	return 0
+}
+
+type ListWorkspacesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Only workspaces this job references; all when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkspacesRequest) Reset() {
+	*x = ListWorkspacesRequest{}
+	mi := &file_collector_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspacesRequest) ProtoMessage() {}
+
+func (x *ListWorkspacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkspacesRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkspacesRequest) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListWorkspacesRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
 	}
 	return ""
 }
 
-type CollectorResponse struct {
+type WorkspaceInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Root          string                 `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"` // Collected files; differs from path for local sources read in place
+	SourceType    string                 `protobuf:"bytes,4,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`
+	Jobs          []string               `protobuf:"bytes,5,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,6,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Ready         bool                   `protobuf:"varint,7,opt,name=ready,proto3" json:"ready,omitempty"` // false while the collection is running
+	CreatedUnix   int64                  `protobuf:"varint,8,opt,name=created_unix,json=createdUnix,proto3" json:"created_unix,omitempty"`
+	LastUsedUnix  int64                  `protobuf:"varint,9,opt,name=last_used_unix,json=lastUsedUnix,proto3" json:"last_used_unix,omitempty"`
+	ExpiresUnix   int64                  `protobuf:"varint,10,opt,name=expires_unix,json=expiresUnix,proto3" json:"expires_unix,omitempty"` // 0 when workspaces do not expire
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CollectorResponse) Reset() {
-	*x = CollectorResponse{}
-	mi := &file_collector_proto_msgTypes[5]
+func (x *WorkspaceInfo) Reset() {
+	*x = WorkspaceInfo{}
+	mi := &file_collector_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CollectorResponse) String() string {
+func (x *WorkspaceInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CollectorResponse) ProtoMessage() {}
+func (*WorkspaceInfo) ProtoMessage() {}
 
-func (x *CollectorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_collector_proto_msgTypes[5]
+func (x *WorkspaceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -298,53 +2255,381 @@ func (x *CollectorResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CollectorResponse.ProtoReflect.Descriptor instead.
-func (*CollectorResponse) Descriptor() ([]byte, []int) {
-	return file_collector_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use WorkspaceInfo.ProtoReflect.Descriptor instead.
+func (*WorkspaceInfo) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *CollectorResponse) GetMessage() string {
+func (x *WorkspaceInfo) GetId() string {
 	if x != nil {
-		return x.Message
+		return x.Id
 	}
 	return ""
 }
 
-func (x *CollectorResponse) GetPath() string {
+func (x *WorkspaceInfo) GetPath() string {
 	if x != nil {
 		return x.Path
 	}
 	return ""
 }
 
+func (x *WorkspaceInfo) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *WorkspaceInfo) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *WorkspaceInfo) GetJobs() []string {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *WorkspaceInfo) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *WorkspaceInfo) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *WorkspaceInfo) GetCreatedUnix() int64 {
+	if x != nil {
+		return x.CreatedUnix
+	}
+	return 0
+}
+
+func (x *WorkspaceInfo) GetLastUsedUnix() int64 {
+	if x != nil {
+		return x.LastUsedUnix
+	}
+	return 0
+}
+
+func (x *WorkspaceInfo) GetExpiresUnix() int64 {
+	if x != nil {
+		return x.ExpiresUnix
+	}
+	return 0
+}
+
+type WorkspaceList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workspaces    []*WorkspaceInfo       `protobuf:"bytes,1,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+	UsedBytes     int64                  `protobuf:"varint,2,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`
+	QuotaBytes    int64                  `protobuf:"varint,3,opt,name=quota_bytes,json=quotaBytes,proto3" json:"quota_bytes,omitempty"` // 0 when unlimited
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkspaceList) Reset() {
+	*x = WorkspaceList{}
+	mi := &file_collector_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceList) ProtoMessage() {}
+
+func (x *WorkspaceList) ProtoReflect() protoreflect.Message {
+	mi := &file_collector_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceList.ProtoReflect.Descriptor instead.
+func (*WorkspaceList) Descriptor() ([]byte, []int) {
+	return file_collector_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WorkspaceList) GetWorkspaces() []*WorkspaceInfo {
+	if x != nil {
+		return x.Workspaces
+	}
+	return nil
+}
+
+func (x *WorkspaceList) GetUsedBytes() int64 {
+	if x != nil {
+		return x.UsedBytes
+	}
+	return 0
+}
+
+func (x *WorkspaceList) GetQuotaBytes() int64 {
+	if x != nil {
+		return x.QuotaBytes
+	}
+	return 0
+}
+
 var File_collector_proto protoreflect.FileDescriptor
 
 const file_collector_proto_rawDesc = "" +
 	"\n" +
-	"\x0fcollector.proto\x12\vcollectorpb\"L\n" +
+	"\x0fcollector.proto\x12\vcollectorpb\"\x9f\x03\n" +
 	"\n" +
 	"GitRequest\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
 	"\x06branch\x18\x02 \x01(\tR\x06branch\x12\x14\n" +
-	"\x05token\x18\x03 \x01(\tR\x05token\"\"\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12\x16\n" +
+	"\x06commit\x18\x04 \x01(\tR\x06commit\x12\x10\n" +
+	"\x03tag\x18\x05 \x01(\tR\x03tag\x12\x18\n" +
+	"\asubpath\x18\x06 \x01(\tR\asubpath\x12\x14\n" +
+	"\x05depth\x18\a \x01(\x05R\x05depth\x12\x1e\n" +
+	"\n" +
+	"submodules\x18\b \x01(\bR\n" +
+	"submodules\x12\x10\n" +
+	"\x03lfs\x18\t \x01(\bR\x03lfs\x12&\n" +
+	"\x0fssh_private_key\x18\n" +
+	" \x01(\tR\rsshPrivateKey\x12&\n" +
+	"\x0fssh_known_hosts\x18\v \x01(\tR\rsshKnownHosts\x12\x1e\n" +
+	"\vssh_key_ref\x18\f \x01(\tR\tsshKeyRef\x12\x15\n" +
+	"\x06job_id\x18\r \x01(\tR\x05jobId\x12\x19\n" +
+	"\bbase_ref\x18\x0e \x01(\tR\abaseRef\x12#\n" +
+	"\rhistory_depth\x18\x0f \x01(\x05R\fhistoryDepth\"t\n" +
 	"\x0eArchiveRequest\x12\x10\n" +
-	"\x03url\x18\x01 \x01(\tR\x03url\"\x1e\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12!\n" +
+	"\fnested_depth\x18\x02 \x01(\x05R\vnestedDepth\x12\x16\n" +
+	"\x06sha256\x18\x03 \x01(\tR\x06sha256\x12\x15\n" +
+	"\x06job_id\x18\x04 \x01(\tR\x05jobId\"M\n" +
 	"\n" +
 	"URLRequest\x12\x10\n" +
-	"\x03url\x18\x01 \x01(\tR\x03url\"#\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\"U\n" +
+	"\fLocalRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1a\n" +
+	"\bsnapshot\x18\x02 \x01(\bR\bsnapshot\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\"s\n" +
+	"\x0ePackageRequest\x12\x1c\n" +
+	"\tecosystem\x18\x01 \x01(\tR\tecosystem\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x15\n" +
+	"\x06job_id\x18\x04 \x01(\tR\x05jobId\"O\n" +
+	"\fImageRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\"\xcc\x02\n" +
+	"\x0eCollectRequest\x12+\n" +
+	"\x03git\x18\x01 \x01(\v2\x17.collectorpb.GitRequestH\x00R\x03git\x127\n" +
+	"\aarchive\x18\x02 \x01(\v2\x1b.collectorpb.ArchiveRequestH\x00R\aarchive\x12+\n" +
+	"\x03url\x18\x03 \x01(\v2\x17.collectorpb.URLRequestH\x00R\x03url\x121\n" +
+	"\x05local\x18\x04 \x01(\v2\x19.collectorpb.LocalRequestH\x00R\x05local\x127\n" +
+	"\apackage\x18\x05 \x01(\v2\x1b.collectorpb.PackageRequestH\x00R\apackage\x121\n" +
+	"\x05image\x18\x06 \x01(\v2\x19.collectorpb.ImageRequestH\x00R\x05imageB\b\n" +
+	"\x06source\"\x8d\x01\n" +
+	"\fCollectEvent\x12:\n" +
+	"\bprogress\x18\x01 \x01(\v2\x1c.collectorpb.CollectProgressH\x00R\bprogress\x128\n" +
+	"\x06result\x18\x02 \x01(\v2\x1e.collectorpb.CollectorResponseH\x00R\x06resultB\a\n" +
+	"\x05event\"\xa9\x01\n" +
+	"\x0fCollectProgress\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x12\n" +
+	"\x04step\x18\x02 \x01(\tR\x04step\x12\x18\n" +
+	"\apercent\x18\x03 \x01(\x05R\apercent\x12\x12\n" +
+	"\x04done\x18\x04 \x01(\x03R\x04done\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\x12(\n" +
+	"\x10bytes_per_second\x18\x06 \x01(\x03R\x0ebytesPerSecond\"z\n" +
+	"\x0eUploadMetadata\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\x12!\n" +
+	"\fnested_depth\x18\x03 \x01(\x05R\vnestedDepth\x12\x15\n" +
+	"\x06job_id\x18\x04 \x01(\tR\x05jobId\"Z\n" +
+	"\vUploadChunk\x127\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x1b.collectorpb.UploadMetadataR\bmetadata\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"\xf8\x02\n" +
 	"\x0fValidateRequest\x12\x10\n" +
-	"\x03url\x18\x01 \x01(\tR\x03url\"B\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06branch\x18\x03 \x01(\tR\x06branch\x12\x14\n" +
+	"\x05token\x18\x04 \x01(\tR\x05token\x12\x16\n" +
+	"\x06commit\x18\x05 \x01(\tR\x06commit\x12\x10\n" +
+	"\x03tag\x18\x06 \x01(\tR\x03tag\x12&\n" +
+	"\x0fssh_private_key\x18\a \x01(\tR\rsshPrivateKey\x12&\n" +
+	"\x0fssh_known_hosts\x18\b \x01(\tR\rsshKnownHosts\x12\x1e\n" +
+	"\vssh_key_ref\x18\t \x01(\tR\tsshKeyRef\x12+\n" +
+	"\x11package_ecosystem\x18\n" +
+	" \x01(\tR\x10packageEcosystem\x12!\n" +
+	"\fpackage_name\x18\v \x01(\tR\vpackageName\x12'\n" +
+	"\x0fpackage_version\x18\f \x01(\tR\x0epackageVersion\"\x9d\x01\n" +
 	"\x10ValidateResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"A\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x0fresolved_commit\x18\x03 \x01(\tR\x0eresolvedCommit\x120\n" +
+	"\x14estimated_size_bytes\x18\x04 \x01(\x03R\x12estimatedSizeBytes\"\xa7\x05\n" +
 	"\x11CollectorResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
-	"\x04path\x18\x02 \x01(\tR\x04path2\xca\x02\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1f\n" +
+	"\vrepo_config\x18\x03 \x01(\tR\n" +
+	"repoConfig\x12'\n" +
+	"\x0fresolved_commit\x18\x04 \x01(\tR\x0eresolvedCommit\x12!\n" +
+	"\flfs_pointers\x18\x05 \x03(\tR\vlfsPointers\x12!\n" +
+	"\fworkspace_id\x18\x06 \x01(\tR\vworkspaceId\x12'\n" +
+	"\x0fartifact_digest\x18\a \x01(\tR\x0eartifactDigest\x12K\n" +
+	"\tlanguages\x18\b \x03(\v2-.collectorpb.CollectorResponse.LanguagesEntryR\tlanguages\x126\n" +
+	"\bmanifest\x18\t \x03(\v2\x1a.collectorpb.ManifestEntryR\bmanifest\x127\n" +
+	"\n" +
+	"provenance\x18\n" +
+	" \x01(\v2\x17.collectorpb.ProvenanceR\n" +
+	"provenance\x12\x1f\n" +
+	"\vmerkle_root\x18\v \x01(\tR\n" +
+	"merkleRoot\x120\n" +
+	"\achanges\x18\f \x01(\v2\x16.collectorpb.ChangeSetR\achanges\x12.\n" +
+	"\ahistory\x18\r \x01(\v2\x14.collectorpb.HistoryR\ahistory\x12,\n" +
+	"\x05image\x18\x0e \x01(\v2\x16.collectorpb.ImageInfoR\x05image\x1a<\n" +
+	"\x0eLanguagesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xd9\x03\n" +
+	"\tImageInfo\x12\x1b\n" +
+	"\trepo_tags\x18\x01 \x03(\tR\brepoTags\x12\x0e\n" +
+	"\x02os\x18\x02 \x01(\tR\x02os\x12\"\n" +
+	"\farchitecture\x18\x03 \x01(\tR\farchitecture\x12\x1e\n" +
+	"\n" +
+	"entrypoint\x18\x04 \x03(\tR\n" +
+	"entrypoint\x12\x10\n" +
+	"\x03cmd\x18\x05 \x03(\tR\x03cmd\x12\x10\n" +
+	"\x03env\x18\x06 \x03(\tR\x03env\x12#\n" +
+	"\rexposed_ports\x18\a \x03(\tR\fexposedPorts\x12\x12\n" +
+	"\x04user\x18\b \x01(\tR\x04user\x12\x1f\n" +
+	"\vworking_dir\x18\t \x01(\tR\n" +
+	"workingDir\x12:\n" +
+	"\x06labels\x18\n" +
+	" \x03(\v2\".collectorpb.ImageInfo.LabelsEntryR\x06labels\x12\x16\n" +
+	"\x06layers\x18\v \x01(\x05R\x06layers\x122\n" +
+	"\bpackages\x18\f \x03(\v2\x16.collectorpb.OSPackageR\bpackages\x12\x1a\n" +
+	"\bwarnings\x18\r \x03(\tR\bwarnings\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8f\x01\n" +
+	"\tOSPackage\x12\x18\n" +
+	"\amanager\x18\x01 \x01(\tR\amanager\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\"\n" +
+	"\farchitecture\x18\x04 \x01(\tR\farchitecture\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\"S\n" +
+	"\aHistory\x12\x18\n" +
+	"\acommits\x18\x01 \x01(\x05R\acommits\x12.\n" +
+	"\x05files\x18\x02 \x03(\v2\x18.collectorpb.FileHistoryR\x05files\"\xe1\x01\n" +
+	"\vFileHistory\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\acommits\x18\x02 \x01(\x05R\acommits\x12\x1f\n" +
+	"\vlines_added\x18\x03 \x01(\x03R\n" +
+	"linesAdded\x12#\n" +
+	"\rlines_removed\x18\x04 \x01(\x03R\flinesRemoved\x12\x18\n" +
+	"\aauthors\x18\x05 \x01(\x05R\aauthors\x12#\n" +
+	"\rlast_modified\x18\x06 \x01(\tR\flastModified\x12\x1f\n" +
+	"\vlast_commit\x18\a \x01(\tR\n" +
+	"lastCommit\"\xb7\x01\n" +
+	"\tChangeSet\x12\x19\n" +
+	"\bbase_ref\x18\x01 \x01(\tR\abaseRef\x12\x1f\n" +
+	"\vbase_commit\x18\x02 \x01(\tR\n" +
+	"baseCommit\x12\x1f\n" +
+	"\vhead_commit\x18\x03 \x01(\tR\n" +
+	"headCommit\x12\x1d\n" +
+	"\n" +
+	"merge_base\x18\x04 \x01(\tR\tmergeBase\x12.\n" +
+	"\x05files\x18\x05 \x03(\v2\x18.collectorpb.ChangedFileR\x05files\"\x95\x01\n" +
+	"\vChangedFile\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x19\n" +
+	"\bold_path\x18\x02 \x01(\tR\aoldPath\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06binary\x18\x04 \x01(\bR\x06binary\x12'\n" +
+	"\x05hunks\x18\x05 \x03(\v2\x11.collectorpb.HunkR\x05hunks\"z\n" +
+	"\x04Hunk\x12\x1b\n" +
+	"\told_start\x18\x01 \x01(\x05R\boldStart\x12\x1b\n" +
+	"\told_lines\x18\x02 \x01(\x05R\boldLines\x12\x1b\n" +
+	"\tnew_start\x18\x03 \x01(\x05R\bnewStart\x12\x1b\n" +
+	"\tnew_lines\x18\x04 \x01(\x05R\bnewLines\"\x81\x01\n" +
+	"\rManifestEntry\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x03 \x01(\tR\x06sha256\x12\x14\n" +
+	"\x05class\x18\x04 \x01(\tR\x05class\x12\x1a\n" +
+	"\blanguage\x18\x05 \x01(\tR\blanguage\"\xaf\x01\n" +
+	"\n" +
+	"Provenance\x12\x1f\n" +
+	"\vsource_type\x18\x01 \x01(\tR\n" +
+	"sourceType\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06commit\x18\x03 \x01(\tR\x06commit\x12\x16\n" +
+	"\x06author\x18\x04 \x01(\tR\x06author\x12\x1f\n" +
+	"\vcommit_time\x18\x05 \x01(\tR\n" +
+	"commitTime\x12\x1d\n" +
+	"\n" +
+	"fetched_at\x18\x06 \x01(\tR\tfetchedAt\"S\n" +
+	"\x17AcquireWorkspaceRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\"S\n" +
+	"\x17ReleaseWorkspaceRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\"[\n" +
+	"\x18ReleaseWorkspaceResponse\x12\x18\n" +
+	"\aremoved\x18\x01 \x01(\bR\aremoved\x12%\n" +
+	"\x0eremaining_refs\x18\x02 \x01(\x05R\rremainingRefs\".\n" +
+	"\x15ListWorkspacesRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\x9d\x02\n" +
+	"\rWorkspaceInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
+	"\x04root\x18\x03 \x01(\tR\x04root\x12\x1f\n" +
+	"\vsource_type\x18\x04 \x01(\tR\n" +
+	"sourceType\x12\x12\n" +
+	"\x04jobs\x18\x05 \x03(\tR\x04jobs\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x06 \x01(\x03R\tsizeBytes\x12\x14\n" +
+	"\x05ready\x18\a \x01(\bR\x05ready\x12!\n" +
+	"\fcreated_unix\x18\b \x01(\x03R\vcreatedUnix\x12$\n" +
+	"\x0elast_used_unix\x18\t \x01(\x03R\flastUsedUnix\x12!\n" +
+	"\fexpires_unix\x18\n" +
+	" \x01(\x03R\vexpiresUnix\"\x8b\x01\n" +
+	"\rWorkspaceList\x12:\n" +
+	"\n" +
+	"workspaces\x18\x01 \x03(\v2\x1a.collectorpb.WorkspaceInfoR\n" +
+	"workspaces\x12\x1d\n" +
+	"\n" +
+	"used_bytes\x18\x02 \x01(\x03R\tusedBytes\x12\x1f\n" +
+	"\vquota_bytes\x18\x03 \x01(\x03R\n" +
+	"quotaBytes2\xdf\a\n" +
 	"\x10CollectorService\x12I\n" +
 	"\x0eCollectFromGit\x12\x17.collectorpb.GitRequest\x1a\x1e.collectorpb.CollectorResponse\x12Q\n" +
 	"\x12CollectFromArchive\x12\x1b.collectorpb.ArchiveRequest\x1a\x1e.collectorpb.CollectorResponse\x12I\n" +
 	"\x0eCollectFromURL\x12\x17.collectorpb.URLRequest\x1a\x1e.collectorpb.CollectorResponse\x12M\n" +
-	"\x0eValidateSource\x12\x1c.collectorpb.ValidateRequest\x1a\x1d.collectorpb.ValidateResponseB3Z1github.com/unarya/unarya/lib/proto/pb/collectorpbb\x06proto3"
+	"\x10CollectFromLocal\x12\x19.collectorpb.LocalRequest\x1a\x1e.collectorpb.CollectorResponse\x12Q\n" +
+	"\x12CollectFromPackage\x12\x1b.collectorpb.PackageRequest\x1a\x1e.collectorpb.CollectorResponse\x12M\n" +
+	"\x10CollectFromImage\x12\x19.collectorpb.ImageRequest\x1a\x1e.collectorpb.CollectorResponse\x12I\n" +
+	"\rCollectStream\x12\x1b.collectorpb.CollectRequest\x1a\x19.collectorpb.CollectEvent0\x01\x12J\n" +
+	"\fUploadSource\x12\x18.collectorpb.UploadChunk\x1a\x1e.collectorpb.CollectorResponse(\x01\x12M\n" +
+	"\x0eValidateSource\x12\x1c.collectorpb.ValidateRequest\x1a\x1d.collectorpb.ValidateResponse\x12X\n" +
+	"\x10AcquireWorkspace\x12$.collectorpb.AcquireWorkspaceRequest\x1a\x1e.collectorpb.CollectorResponse\x12_\n" +
+	"\x10ReleaseWorkspace\x12$.collectorpb.ReleaseWorkspaceRequest\x1a%.collectorpb.ReleaseWorkspaceResponse\x12P\n" +
+	"\x0eListWorkspaces\x12\".collectorpb.ListWorkspacesRequest\x1a\x1a.collectorpb.WorkspaceListB3Z1github.com/unarya/unarya/lib/proto/pb/collectorpbb\x06proto3"
 
 var (
 	file_collector_proto_rawDescOnce sync.Once
@@ -358,29 +2643,91 @@ func file_collector_proto_rawDescGZIP() []byte {
 	return file_collector_proto_rawDescData
 }
 
-var file_collector_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_collector_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_collector_proto_goTypes = []any{
-	(*GitRequest)(nil),        // 0: collectorpb.GitRequest
-	(*ArchiveRequest)(nil),    // 1: collectorpb.ArchiveRequest
-	(*URLRequest)(nil),        // 2: collectorpb.URLRequest
-	(*ValidateRequest)(nil),   // 3: collectorpb.ValidateRequest
-	(*ValidateResponse)(nil),  // 4: collectorpb.ValidateResponse
-	(*CollectorResponse)(nil), // 5: collectorpb.CollectorResponse
+	(*GitRequest)(nil),               // 0: collectorpb.GitRequest
+	(*ArchiveRequest)(nil),           // 1: collectorpb.ArchiveRequest
+	(*URLRequest)(nil),               // 2: collectorpb.URLRequest
+	(*LocalRequest)(nil),             // 3: collectorpb.LocalRequest
+	(*PackageRequest)(nil),           // 4: collectorpb.PackageRequest
+	(*ImageRequest)(nil),             // 5: collectorpb.ImageRequest
+	(*CollectRequest)(nil),           // 6: collectorpb.CollectRequest
+	(*CollectEvent)(nil),             // 7: collectorpb.CollectEvent
+	(*CollectProgress)(nil),          // 8: collectorpb.CollectProgress
+	(*UploadMetadata)(nil),           // 9: collectorpb.UploadMetadata
+	(*UploadChunk)(nil),              // 10: collectorpb.UploadChunk
+	(*ValidateRequest)(nil),          // 11: collectorpb.ValidateRequest
+	(*ValidateResponse)(nil),         // 12: collectorpb.ValidateResponse
+	(*CollectorResponse)(nil),        // 13: collectorpb.CollectorResponse
+	(*ImageInfo)(nil),                // 14: collectorpb.ImageInfo
+	(*OSPackage)(nil),                // 15: collectorpb.OSPackage
+	(*History)(nil),                  // 16: collectorpb.History
+	(*FileHistory)(nil),              // 17: collectorpb.FileHistory
+	(*ChangeSet)(nil),                // 18: collectorpb.ChangeSet
+	(*ChangedFile)(nil),              // 19: collectorpb.ChangedFile
+	(*Hunk)(nil),                     // 20: collectorpb.Hunk
+	(*ManifestEntry)(nil),            // 21: collectorpb.ManifestEntry
+	(*Provenance)(nil),               // 22: collectorpb.Provenance
+	(*AcquireWorkspaceRequest)(nil),  // 23: collectorpb.AcquireWorkspaceRequest
+	(*ReleaseWorkspaceRequest)(nil),  // 24: collectorpb.ReleaseWorkspaceRequest
+	(*ReleaseWorkspaceResponse)(nil), // 25: collectorpb.ReleaseWorkspaceResponse
+	(*ListWorkspacesRequest)(nil),    // 26: collectorpb.ListWorkspacesRequest
+	(*WorkspaceInfo)(nil),            // 27: collectorpb.WorkspaceInfo
+	(*WorkspaceList)(nil),            // 28: collectorpb.WorkspaceList
+	nil,                              // 29: collectorpb.CollectorResponse.LanguagesEntry
+	nil,                              // 30: collectorpb.ImageInfo.LabelsEntry
 }
 var file_collector_proto_depIdxs = []int32{
-	0, // 0: collectorpb.CollectorService.CollectFromGit:input_type -> collectorpb.GitRequest
-	1, // 1: collectorpb.CollectorService.CollectFromArchive:input_type -> collectorpb.ArchiveRequest
-	2, // 2: collectorpb.CollectorService.CollectFromURL:input_type -> collectorpb.URLRequest
-	3, // 3: collectorpb.CollectorService.ValidateSource:input_type -> collectorpb.ValidateRequest
-	5, // 4: collectorpb.CollectorService.CollectFromGit:output_type -> collectorpb.CollectorResponse
-	5, // 5: collectorpb.CollectorService.CollectFromArchive:output_type -> collectorpb.CollectorResponse
-	5, // 6: collectorpb.CollectorService.CollectFromURL:output_type -> collectorpb.CollectorResponse
-	4, // 7: collectorpb.CollectorService.ValidateSource:output_type -> collectorpb.ValidateResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: collectorpb.CollectRequest.git:type_name -> collectorpb.GitRequest
+	1,  // 1: collectorpb.CollectRequest.archive:type_name -> collectorpb.ArchiveRequest
+	2,  // 2: collectorpb.CollectRequest.url:type_name -> collectorpb.URLRequest
+	3,  // 3: collectorpb.CollectRequest.local:type_name -> collectorpb.LocalRequest
+	4,  // 4: collectorpb.CollectRequest.package:type_name -> collectorpb.PackageRequest
+	5,  // 5: collectorpb.CollectRequest.image:type_name -> collectorpb.ImageRequest
+	8,  // 6: collectorpb.CollectEvent.progress:type_name -> collectorpb.CollectProgress
+	13, // 7: collectorpb.CollectEvent.result:type_name -> collectorpb.CollectorResponse
+	9,  // 8: collectorpb.UploadChunk.metadata:type_name -> collectorpb.UploadMetadata
+	29, // 9: collectorpb.CollectorResponse.languages:type_name -> collectorpb.CollectorResponse.LanguagesEntry
+	21, // 10: collectorpb.CollectorResponse.manifest:type_name -> collectorpb.ManifestEntry
+	22, // 11: collectorpb.CollectorResponse.provenance:type_name -> collectorpb.Provenance
+	18, // 12: collectorpb.CollectorResponse.changes:type_name -> collectorpb.ChangeSet
+	16, // 13: collectorpb.CollectorResponse.history:type_name -> collectorpb.History
+	14, // 14: collectorpb.CollectorResponse.image:type_name -> collectorpb.ImageInfo
+	30, // 15: collectorpb.ImageInfo.labels:type_name -> collectorpb.ImageInfo.LabelsEntry
+	15, // 16: collectorpb.ImageInfo.packages:type_name -> collectorpb.OSPackage
+	17, // 17: collectorpb.History.files:type_name -> collectorpb.FileHistory
+	19, // 18: collectorpb.ChangeSet.files:type_name -> collectorpb.ChangedFile
+	20, // 19: collectorpb.ChangedFile.hunks:type_name -> collectorpb.Hunk
+	27, // 20: collectorpb.WorkspaceList.workspaces:type_name -> collectorpb.WorkspaceInfo
+	0,  // 21: collectorpb.CollectorService.CollectFromGit:input_type -> collectorpb.GitRequest
+	1,  // 22: collectorpb.CollectorService.CollectFromArchive:input_type -> collectorpb.ArchiveRequest
+	2,  // 23: collectorpb.CollectorService.CollectFromURL:input_type -> collectorpb.URLRequest
+	3,  // 24: collectorpb.CollectorService.CollectFromLocal:input_type -> collectorpb.LocalRequest
+	4,  // 25: collectorpb.CollectorService.CollectFromPackage:input_type -> collectorpb.PackageRequest
+	5,  // 26: collectorpb.CollectorService.CollectFromImage:input_type -> collectorpb.ImageRequest
+	6,  // 27: collectorpb.CollectorService.CollectStream:input_type -> collectorpb.CollectRequest
+	10, // 28: collectorpb.CollectorService.UploadSource:input_type -> collectorpb.UploadChunk
+	11, // 29: collectorpb.CollectorService.ValidateSource:input_type -> collectorpb.ValidateRequest
+	23, // 30: collectorpb.CollectorService.AcquireWorkspace:input_type -> collectorpb.AcquireWorkspaceRequest
+	24, // 31: collectorpb.CollectorService.ReleaseWorkspace:input_type -> collectorpb.ReleaseWorkspaceRequest
+	26, // 32: collectorpb.CollectorService.ListWorkspaces:input_type -> collectorpb.ListWorkspacesRequest
+	13, // 33: collectorpb.CollectorService.CollectFromGit:output_type -> collectorpb.CollectorResponse
+	13, // 34: collectorpb.CollectorService.CollectFromArchive:output_type -> collectorpb.CollectorResponse
+	13, // 35: collectorpb.CollectorService.CollectFromURL:output_type -> collectorpb.CollectorResponse
+	13, // 36: collectorpb.CollectorService.CollectFromLocal:output_type -> collectorpb.CollectorResponse
+	13, // 37: collectorpb.CollectorService.CollectFromPackage:output_type -> collectorpb.CollectorResponse
+	13, // 38: collectorpb.CollectorService.CollectFromImage:output_type -> collectorpb.CollectorResponse
+	7,  // 39: collectorpb.CollectorService.CollectStream:output_type -> collectorpb.CollectEvent
+	13, // 40: collectorpb.CollectorService.UploadSource:output_type -> collectorpb.CollectorResponse
+	12, // 41: collectorpb.CollectorService.ValidateSource:output_type -> collectorpb.ValidateResponse
+	13, // 42: collectorpb.CollectorService.AcquireWorkspace:output_type -> collectorpb.CollectorResponse
+	25, // 43: collectorpb.CollectorService.ReleaseWorkspace:output_type -> collectorpb.ReleaseWorkspaceResponse
+	28, // 44: collectorpb.CollectorService.ListWorkspaces:output_type -> collectorpb.WorkspaceList
+	33, // [33:45] is the sub-list for method output_type
+	21, // [21:33] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_collector_proto_init() }
@@ -388,13 +2735,25 @@ func file_collector_proto_init() {
 	if File_collector_proto != nil {
 		return
 	}
+	file_collector_proto_msgTypes[6].OneofWrappers = []any{
+		(*CollectRequest_Git)(nil),
+		(*CollectRequest_Archive)(nil),
+		(*CollectRequest_Url)(nil),
+		(*CollectRequest_Local)(nil),
+		(*CollectRequest_Package)(nil),
+		(*CollectRequest_Image)(nil),
+	}
+	file_collector_proto_msgTypes[7].OneofWrappers = []any{
+		(*CollectEvent_Progress)(nil),
+		(*CollectEvent_Result)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_collector_proto_rawDesc), len(file_collector_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,7 +22,15 @@ const (
 	CollectorService_CollectFromGit_FullMethodName     = "/collectorpb.CollectorService/CollectFromGit"
 	CollectorService_CollectFromArchive_FullMethodName = "/collectorpb.CollectorService/CollectFromArchive"
 	CollectorService_CollectFromURL_FullMethodName     = "/collectorpb.CollectorService/CollectFromURL"
+	CollectorService_CollectFromLocal_FullMethodName   = "/collectorpb.CollectorService/CollectFromLocal"
+	CollectorService_CollectFromPackage_FullMethodName = "/collectorpb.CollectorService/CollectFromPackage"
+	CollectorService_CollectFromImage_FullMethodName   = "/collectorpb.CollectorService/CollectFromImage"
+	CollectorService_CollectStream_FullMethodName      = "/collectorpb.CollectorService/CollectStream"
+	CollectorService_UploadSource_FullMethodName       = "/collectorpb.CollectorService/UploadSource"
 	CollectorService_ValidateSource_FullMethodName     = "/collectorpb.CollectorService/ValidateSource"
+	CollectorService_AcquireWorkspace_FullMethodName   = "/collectorpb.CollectorService/AcquireWorkspace"
+	CollectorService_ReleaseWorkspace_FullMethodName   = "/collectorpb.CollectorService/ReleaseWorkspace"
+	CollectorService_ListWorkspaces_FullMethodName     = "/collectorpb.CollectorService/ListWorkspaces"
 )
 
 // CollectorServiceClient is the client API for CollectorService service.
@@ -38,8 +46,28 @@ type CollectorServiceClient interface {
 	CollectFromArchive(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (*CollectorResponse, error)
 	// Download files from HTTP/HTTPS
 	CollectFromURL(ctx context.Context, in *URLRequest, opts ...grpc.CallOption) (*CollectorResponse, error)
+	// Collect a directory already on the collector's filesystem
+	CollectFromLocal(ctx context.Context, in *LocalRequest, opts ...grpc.CallOption) (*CollectorResponse, error)
+	// Download a package version from the Go proxy, npm or PyPI and verify
+	// the checksums the registry publishes
+	CollectFromPackage(ctx context.Context, in *PackageRequest, opts ...grpc.CallOption) (*CollectorResponse, error)
+	// Download a docker save or OCI layout tarball, flatten its layers and
+	// inventory its OS packages
+	CollectFromImage(ctx context.Context, in *ImageRequest, opts ...grpc.CallOption) (*CollectorResponse, error)
+	// Collect any source like the unary RPCs above, streaming progress
+	// events and then the CollectorResponse
+	CollectStream(ctx context.Context, in *CollectRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CollectEvent], error)
+	// Receive an archive pushed by the client in chunks. The first message
+	// carries the metadata; every message may carry data.
+	UploadSource(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, CollectorResponse], error)
 	// Validate incoming source URL
 	ValidateSource(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	// Add a job's reference to a finished workspace and describe its collection
+	AcquireWorkspace(ctx context.Context, in *AcquireWorkspaceRequest, opts ...grpc.CallOption) (*CollectorResponse, error)
+	// Drop a job's reference to a workspace; it is deleted with the last one
+	ReleaseWorkspace(ctx context.Context, in *ReleaseWorkspaceRequest, opts ...grpc.CallOption) (*ReleaseWorkspaceResponse, error)
+	// List workspaces and disk usage
+	ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest, opts ...grpc.CallOption) (*WorkspaceList, error)
 }
 
 type collectorServiceClient struct {
@@ -80,6 +108,68 @@ func (c *collectorServiceClient) CollectFromURL(ctx context.Context, in *URLRequ
 	return out, nil
 }
 
+func (c *collectorServiceClient) CollectFromLocal(ctx context.Context, in *LocalRequest, opts ...grpc.CallOption) (*CollectorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectorResponse)
+	err := c.cc.Invoke(ctx, CollectorService_CollectFromLocal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collectorServiceClient) CollectFromPackage(ctx context.Context, in *PackageRequest, opts ...grpc.CallOption) (*CollectorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectorResponse)
+	err := c.cc.Invoke(ctx, CollectorService_CollectFromPackage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collectorServiceClient) CollectFromImage(ctx context.Context, in *ImageRequest, opts ...grpc.CallOption) (*CollectorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectorResponse)
+	err := c.cc.Invoke(ctx, CollectorService_CollectFromImage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collectorServiceClient) CollectStream(ctx context.Context, in *CollectRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CollectEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CollectorService_ServiceDesc.Streams[0], CollectorService_CollectStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CollectRequest, CollectEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CollectorService_CollectStreamClient = grpc.ServerStreamingClient[CollectEvent]
+
+func (c *collectorServiceClient) UploadSource(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, CollectorResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CollectorService_ServiceDesc.Streams[1], CollectorService_UploadSource_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadChunk, CollectorResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CollectorService_UploadSourceClient = grpc.ClientStreamingClient[UploadChunk, CollectorResponse]
+
 func (c *collectorServiceClient) ValidateSource(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ValidateResponse)
@@ -90,6 +180,36 @@ func (c *collectorServiceClient) ValidateSource(ctx context.Context, in *Validat
 	return out, nil
 }
 
+func (c *collectorServiceClient) AcquireWorkspace(ctx context.Context, in *AcquireWorkspaceRequest, opts ...grpc.CallOption) (*CollectorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectorResponse)
+	err := c.cc.Invoke(ctx, CollectorService_AcquireWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collectorServiceClient) ReleaseWorkspace(ctx context.Context, in *ReleaseWorkspaceRequest, opts ...grpc.CallOption) (*ReleaseWorkspaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseWorkspaceResponse)
+	err := c.cc.Invoke(ctx, CollectorService_ReleaseWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collectorServiceClient) ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest, opts ...grpc.CallOption) (*WorkspaceList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkspaceList)
+	err := c.cc.Invoke(ctx, CollectorService_ListWorkspaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CollectorServiceServer is the server API for CollectorService service.
 // All implementations must embed UnimplementedCollectorServiceServer
 // for forward compatibility.
@@ -103,8 +223,28 @@ type CollectorServiceServer interface {
 	CollectFromArchive(context.Context, *ArchiveRequest) (*CollectorResponse, error)
 	// Download files from HTTP/HTTPS
 	CollectFromURL(context.Context, *URLRequest) (*CollectorResponse, error)
+	// Collect a directory already on the collector's filesystem
+	CollectFromLocal(context.Context, *LocalRequest) (*CollectorResponse, error)
+	// Download a package version from the Go proxy, npm or PyPI and verify
+	// the checksums the registry publishes
+	CollectFromPackage(context.Context, *PackageRequest) (*CollectorResponse, error)
+	// Download a docker save or OCI layout tarball, flatten its layers and
+	// inventory its OS packages
+	CollectFromImage(context.Context, *ImageRequest) (*CollectorResponse, error)
+	// Collect any source like the unary RPCs above, streaming progress
+	// events and then the CollectorResponse
+	CollectStream(*CollectRequest, grpc.ServerStreamingServer[CollectEvent]) error
+	// Receive an archive pushed by the client in chunks. The first message
+	// carries the metadata; every message may carry data.
+	UploadSource(grpc.ClientStreamingServer[UploadChunk, CollectorResponse]) error
 	// Validate incoming source URL
 	ValidateSource(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	// Add a job's reference to a finished workspace and describe its collection
+	AcquireWorkspace(context.Context, *AcquireWorkspaceRequest) (*CollectorResponse, error)
+	// Drop a job's reference to a workspace; it is deleted with the last one
+	ReleaseWorkspace(context.Context, *ReleaseWorkspaceRequest) (*ReleaseWorkspaceResponse, error)
+	// List workspaces and disk usage
+	ListWorkspaces(context.Context, *ListWorkspacesRequest) (*WorkspaceList, error)
 	mustEmbedUnimplementedCollectorServiceServer()
 }
 
@@ -124,9 +264,33 @@ func (UnimplementedCollectorServiceServer) CollectFromArchive(context.Context, *
 func (UnimplementedCollectorServiceServer) CollectFromURL(context.Context, *URLRequest) (*CollectorResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CollectFromURL not implemented")
 }
+func (UnimplementedCollectorServiceServer) CollectFromLocal(context.Context, *LocalRequest) (*CollectorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectFromLocal not implemented")
+}
+func (UnimplementedCollectorServiceServer) CollectFromPackage(context.Context, *PackageRequest) (*CollectorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectFromPackage not implemented")
+}
+func (UnimplementedCollectorServiceServer) CollectFromImage(context.Context, *ImageRequest) (*CollectorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectFromImage not implemented")
+}
+func (UnimplementedCollectorServiceServer) CollectStream(*CollectRequest, grpc.ServerStreamingServer[CollectEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method CollectStream not implemented")
+}
+func (UnimplementedCollectorServiceServer) UploadSource(grpc.ClientStreamingServer[UploadChunk, CollectorResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadSource not implemented")
+}
 func (UnimplementedCollectorServiceServer) ValidateSource(context.Context, *ValidateRequest) (*ValidateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ValidateSource not implemented")
 }
+func (UnimplementedCollectorServiceServer) AcquireWorkspace(context.Context, *AcquireWorkspaceRequest) (*CollectorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcquireWorkspace not implemented")
+}
+func (UnimplementedCollectorServiceServer) ReleaseWorkspace(context.Context, *ReleaseWorkspaceRequest) (*ReleaseWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseWorkspace not implemented")
+}
+func (UnimplementedCollectorServiceServer) ListWorkspaces(context.Context, *ListWorkspacesRequest) (*WorkspaceList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkspaces not implemented")
+}
 func (UnimplementedCollectorServiceServer) mustEmbedUnimplementedCollectorServiceServer() {}
 func (UnimplementedCollectorServiceServer) testEmbeddedByValue()                          {}
 
@@ -202,6 +366,78 @@ func _CollectorService_CollectFromURL_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CollectorService_CollectFromLocal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServiceServer).CollectFromLocal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CollectorService_CollectFromLocal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServiceServer).CollectFromLocal(ctx, req.(*LocalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CollectorService_CollectFromPackage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PackageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServiceServer).CollectFromPackage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CollectorService_CollectFromPackage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServiceServer).CollectFromPackage(ctx, req.(*PackageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CollectorService_CollectFromImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServiceServer).CollectFromImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CollectorService_CollectFromImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServiceServer).CollectFromImage(ctx, req.(*ImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CollectorService_CollectStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CollectRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CollectorServiceServer).CollectStream(m, &grpc.GenericServerStream[CollectRequest, CollectEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CollectorService_CollectStreamServer = grpc.ServerStreamingServer[CollectEvent]
+
+func _CollectorService_UploadSource_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CollectorServiceServer).UploadSource(&grpc.GenericServerStream[UploadChunk, CollectorResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CollectorService_UploadSourceServer = grpc.ClientStreamingServer[UploadChunk, CollectorResponse]
+
 func _CollectorService_ValidateSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ValidateRequest)
 	if err := dec(in); err != nil {
@@ -220,6 +456,60 @@ func _CollectorService_ValidateSource_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CollectorService_AcquireWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcquireWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServiceServer).AcquireWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CollectorService_AcquireWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServiceServer).AcquireWorkspace(ctx, req.(*AcquireWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CollectorService_ReleaseWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServiceServer).ReleaseWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CollectorService_ReleaseWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServiceServer).ReleaseWorkspace(ctx, req.(*ReleaseWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CollectorService_ListWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkspacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServiceServer).ListWorkspaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CollectorService_ListWorkspaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServiceServer).ListWorkspaces(ctx, req.(*ListWorkspacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CollectorService_ServiceDesc is the grpc.ServiceDesc for CollectorService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -239,11 +529,46 @@ var CollectorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CollectFromURL",
 			Handler:    _CollectorService_CollectFromURL_Handler,
 		},
+		{
+			MethodName: "CollectFromLocal",
+			Handler:    _CollectorService_CollectFromLocal_Handler,
+		},
+		{
+			MethodName: "CollectFromPackage",
+			Handler:    _CollectorService_CollectFromPackage_Handler,
+		},
+		{
+			MethodName: "CollectFromImage",
+			Handler:    _CollectorService_CollectFromImage_Handler,
+		},
 		{
 			MethodName: "ValidateSource",
 			Handler:    _CollectorService_ValidateSource_Handler,
 		},
+		{
+			MethodName: "AcquireWorkspace",
+			Handler:    _CollectorService_AcquireWorkspace_Handler,
+		},
+		{
+			MethodName: "ReleaseWorkspace",
+			Handler:    _CollectorService_ReleaseWorkspace_Handler,
+		},
+		{
+			MethodName: "ListWorkspaces",
+			Handler:    _CollectorService_ListWorkspaces_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CollectStream",
+			Handler:       _CollectorService_CollectStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadSource",
+			Handler:       _CollectorService_UploadSource_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "collector.proto",
 }
@@ -22,10 +22,33 @@ const (
 )
 
 type PipelineRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RepositoryUrl string                 `protobuf:"bytes,1,opt,name=repository_url,json=repositoryUrl,proto3" json:"repository_url,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RepositoryUrl    string                 `protobuf:"bytes,1,opt,name=repository_url,json=repositoryUrl,proto3" json:"repository_url,omitempty"`
+	Branch           string                 `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
+	Token            string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	SourceType       string                 `protobuf:"bytes,4,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`                    // "git" (default), "archive", "url", "local", "package", "image"
+	Template         string                 `protobuf:"bytes,5,opt,name=template,proto3" json:"template,omitempty"`                                          // Pipeline template, "full" when empty
+	NestedDepth      int32                  `protobuf:"varint,6,opt,name=nested_depth,json=nestedDepth,proto3" json:"nested_depth,omitempty"`                // Archive sources: levels of nested archives to unpack
+	Snapshot         bool                   `protobuf:"varint,7,opt,name=snapshot,proto3" json:"snapshot,omitempty"`                                         // Local sources: copy the directory before analysis
+	Commit           string                 `protobuf:"bytes,8,opt,name=commit,proto3" json:"commit,omitempty"`                                              // Git sources: full commit SHA instead of a branch
+	Tag              string                 `protobuf:"bytes,9,opt,name=tag,proto3" json:"tag,omitempty"`                                                    // Git sources: tag instead of a branch
+	Subpath          string                 `protobuf:"bytes,10,opt,name=subpath,proto3" json:"subpath,omitempty"`                                           // Git sources: analyze only this directory
+	Depth            int32                  `protobuf:"varint,11,opt,name=depth,proto3" json:"depth,omitempty"`                                              // Git sources: history depth; 0 latest commit, -1 full
+	Submodules       bool                   `protobuf:"varint,12,opt,name=submodules,proto3" json:"submodules,omitempty"`                                    // Git sources: check out submodules recursively
+	Lfs              bool                   `protobuf:"varint,13,opt,name=lfs,proto3" json:"lfs,omitempty"`                                                  // Git sources: fetch Git LFS objects
+	SshPrivateKey    string                 `protobuf:"bytes,14,opt,name=ssh_private_key,json=sshPrivateKey,proto3" json:"ssh_private_key,omitempty"`        // SSH git sources: deploy key
+	SshKnownHosts    string                 `protobuf:"bytes,15,opt,name=ssh_known_hosts,json=sshKnownHosts,proto3" json:"ssh_known_hosts,omitempty"`        // SSH git sources: known_hosts lines for the server
+	SshKeyRef        string                 `protobuf:"bytes,16,opt,name=ssh_key_ref,json=sshKeyRef,proto3" json:"ssh_key_ref,omitempty"`                    // SSH git sources: key stored on the collector
+	Sha256           string                 `protobuf:"bytes,17,opt,name=sha256,proto3" json:"sha256,omitempty"`                                             // Archive, URL and image sources: expected checksum, hex
+	BaseRef          string                 `protobuf:"bytes,18,opt,name=base_ref,json=baseRef,proto3" json:"base_ref,omitempty"`                            // Git sources: pull request base; only changes since the merge base are analyzed
+	HistoryDepth     int32                  `protobuf:"varint,19,opt,name=history_depth,json=historyDepth,proto3" json:"history_depth,omitempty"`            // Git sources: commits to mine for churn and authorship; 0 disables
+	PackageEcosystem string                 `protobuf:"bytes,20,opt,name=package_ecosystem,json=packageEcosystem,proto3" json:"package_ecosystem,omitempty"` // Package sources: "go", "npm" or "pypi"
+	PackageName      string                 `protobuf:"bytes,21,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`                // Package sources: module, package or project name
+	PackageVersion   string                 `protobuf:"bytes,22,opt,name=package_version,json=packageVersion,proto3" json:"package_version,omitempty"`       // Package sources: exact version
+	JobId            string                 `protobuf:"bytes,23,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`                                  // Optional ID for the job, so WatchJob can follow it before StartPipeline returns
+	WorkspaceId      string                 `protobuf:"bytes,24,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`                // Analyze this collector workspace instead of collecting; the job takes its own reference
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *PipelineRequest) Reset() {
@@ -65,12 +88,177 @@ func (x *PipelineRequest) GetRepositoryUrl() string {
 	return ""
 }
 
+func (x *PipelineRequest) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetNestedDepth() int32 {
+	if x != nil {
+		return x.NestedDepth
+	}
+	return 0
+}
+
+func (x *PipelineRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+func (x *PipelineRequest) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetSubpath() string {
+	if x != nil {
+		return x.Subpath
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *PipelineRequest) GetSubmodules() bool {
+	if x != nil {
+		return x.Submodules
+	}
+	return false
+}
+
+func (x *PipelineRequest) GetLfs() bool {
+	if x != nil {
+		return x.Lfs
+	}
+	return false
+}
+
+func (x *PipelineRequest) GetSshPrivateKey() string {
+	if x != nil {
+		return x.SshPrivateKey
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetSshKnownHosts() string {
+	if x != nil {
+		return x.SshKnownHosts
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetSshKeyRef() string {
+	if x != nil {
+		return x.SshKeyRef
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetBaseRef() string {
+	if x != nil {
+		return x.BaseRef
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetHistoryDepth() int32 {
+	if x != nil {
+		return x.HistoryDepth
+	}
+	return 0
+}
+
+func (x *PipelineRequest) GetPackageEcosystem() string {
+	if x != nil {
+		return x.PackageEcosystem
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetPackageVersion() string {
+	if x != nil {
+		return x.PackageVersion
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *PipelineRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
 type PipelineResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	Details       string                 `protobuf:"bytes,2,opt,name=details,proto3" json:"details,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Status         string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Details        string                 `protobuf:"bytes,2,opt,name=details,proto3" json:"details,omitempty"`
+	JobId          string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	ConfigErrors   []string               `protobuf:"bytes,4,rep,name=config_errors,json=configErrors,proto3" json:"config_errors,omitempty"`       // Problems found in the repository's .unarya.yml
+	ResolvedCommit string                 `protobuf:"bytes,5,opt,name=resolved_commit,json=resolvedCommit,proto3" json:"resolved_commit,omitempty"` // Commit that was analyzed (git only)
+	MerkleRoot     string                 `protobuf:"bytes,6,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`             // Merkle root of the collected files' manifest
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *PipelineResponse) Reset() {
@@ -117,18 +305,472 @@ func (x *PipelineResponse) GetDetails() string {
 	return ""
 }
 
+func (x *PipelineResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *PipelineResponse) GetConfigErrors() []string {
+	if x != nil {
+		return x.ConfigErrors
+	}
+	return nil
+}
+
+func (x *PipelineResponse) GetResolvedCommit() string {
+	if x != nil {
+		return x.ResolvedCommit
+	}
+	return ""
+}
+
+func (x *PipelineResponse) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+type PipelinePlan struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Valid              bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Template           string                 `protobuf:"bytes,2,opt,name=template,proto3" json:"template,omitempty"`
+	Stages             []*PlannedStage        `protobuf:"bytes,3,rep,name=stages,proto3" json:"stages,omitempty"`
+	ResolvedCommit     string                 `protobuf:"bytes,4,opt,name=resolved_commit,json=resolvedCommit,proto3" json:"resolved_commit,omitempty"`                // Commit the branch points to (git only)
+	EstimatedSizeBytes int64                  `protobuf:"varint,5,opt,name=estimated_size_bytes,json=estimatedSizeBytes,proto3" json:"estimated_size_bytes,omitempty"` // 0 when the size is unknown
+	Errors             []string               `protobuf:"bytes,6,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *PipelinePlan) Reset() {
+	*x = PipelinePlan{}
+	mi := &file_orchestrator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PipelinePlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PipelinePlan) ProtoMessage() {}
+
+func (x *PipelinePlan) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PipelinePlan.ProtoReflect.Descriptor instead.
+func (*PipelinePlan) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PipelinePlan) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *PipelinePlan) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *PipelinePlan) GetStages() []*PlannedStage {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *PipelinePlan) GetResolvedCommit() string {
+	if x != nil {
+		return x.ResolvedCommit
+	}
+	return ""
+}
+
+func (x *PipelinePlan) GetEstimatedSizeBytes() int64 {
+	if x != nil {
+		return x.EstimatedSizeBytes
+	}
+	return 0
+}
+
+func (x *PipelinePlan) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type PlannedStage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"` // Service address
+	Reachable     bool                   `protobuf:"varint,3,opt,name=reachable,proto3" json:"reachable,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlannedStage) Reset() {
+	*x = PlannedStage{}
+	mi := &file_orchestrator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlannedStage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlannedStage) ProtoMessage() {}
+
+func (x *PlannedStage) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlannedStage.ProtoReflect.Descriptor instead.
+func (*PlannedStage) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PlannedStage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlannedStage) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *PlannedStage) GetReachable() bool {
+	if x != nil {
+		return x.Reachable
+	}
+	return false
+}
+
+func (x *PlannedStage) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type WatchJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchJobRequest) Reset() {
+	*x = WatchJobRequest{}
+	mi := &file_orchestrator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchJobRequest) ProtoMessage() {}
+
+func (x *WatchJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchJobRequest.ProtoReflect.Descriptor instead.
+func (*WatchJobRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WatchJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type JobEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`     // queued, running, success, failed, cancelled or interrupted
+	Stage         string                 `protobuf:"bytes,3,opt,name=stage,proto3" json:"stage,omitempty"`       // Pipeline stage running, e.g. collector
+	Details       string                 `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`   // Set once the job has finished
+	Time          string                 `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`         // RFC 3339
+	Progress      *JobProgress           `protobuf:"bytes,6,opt,name=progress,proto3" json:"progress,omitempty"` // Collection progress relayed from the collector
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobEvent) Reset() {
+	*x = JobEvent{}
+	mi := &file_orchestrator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobEvent) ProtoMessage() {}
+
+func (x *JobEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobEvent.ProtoReflect.Descriptor instead.
+func (*JobEvent) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *JobEvent) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobEvent) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *JobEvent) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *JobEvent) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *JobEvent) GetProgress() *JobProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+type JobProgress struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Stage          string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`      // Collector stage: git, download, extract or scan
+	Step           string                 `protobuf:"bytes,2,opt,name=step,proto3" json:"step,omitempty"`        // e.g. git's "Receiving objects"
+	Percent        int32                  `protobuf:"varint,3,opt,name=percent,proto3" json:"percent,omitempty"` // -1 when the total is unknown
+	Done           int64                  `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`
+	Total          int64                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"` // 0 when unknown
+	BytesPerSecond int64                  `protobuf:"varint,6,opt,name=bytes_per_second,json=bytesPerSecond,proto3" json:"bytes_per_second,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *JobProgress) Reset() {
+	*x = JobProgress{}
+	mi := &file_orchestrator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobProgress) ProtoMessage() {}
+
+func (x *JobProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobProgress.ProtoReflect.Descriptor instead.
+func (*JobProgress) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *JobProgress) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *JobProgress) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *JobProgress) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *JobProgress) GetDone() int64 {
+	if x != nil {
+		return x.Done
+	}
+	return 0
+}
+
+func (x *JobProgress) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *JobProgress) GetBytesPerSecond() int64 {
+	if x != nil {
+		return x.BytesPerSecond
+	}
+	return 0
+}
+
 var File_orchestrator_proto protoreflect.FileDescriptor
 
 const file_orchestrator_proto_rawDesc = "" +
 	"\n" +
-	"\x12orchestrator.proto\x12\x0eorchestratorpb\"8\n" +
+	"\x12orchestrator.proto\x12\x0eorchestratorpb\"\xe9\x05\n" +
 	"\x0fPipelineRequest\x12%\n" +
-	"\x0erepository_url\x18\x01 \x01(\tR\rrepositoryUrl\"D\n" +
+	"\x0erepository_url\x18\x01 \x01(\tR\rrepositoryUrl\x12\x16\n" +
+	"\x06branch\x18\x02 \x01(\tR\x06branch\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12\x1f\n" +
+	"\vsource_type\x18\x04 \x01(\tR\n" +
+	"sourceType\x12\x1a\n" +
+	"\btemplate\x18\x05 \x01(\tR\btemplate\x12!\n" +
+	"\fnested_depth\x18\x06 \x01(\x05R\vnestedDepth\x12\x1a\n" +
+	"\bsnapshot\x18\a \x01(\bR\bsnapshot\x12\x16\n" +
+	"\x06commit\x18\b \x01(\tR\x06commit\x12\x10\n" +
+	"\x03tag\x18\t \x01(\tR\x03tag\x12\x18\n" +
+	"\asubpath\x18\n" +
+	" \x01(\tR\asubpath\x12\x14\n" +
+	"\x05depth\x18\v \x01(\x05R\x05depth\x12\x1e\n" +
+	"\n" +
+	"submodules\x18\f \x01(\bR\n" +
+	"submodules\x12\x10\n" +
+	"\x03lfs\x18\r \x01(\bR\x03lfs\x12&\n" +
+	"\x0fssh_private_key\x18\x0e \x01(\tR\rsshPrivateKey\x12&\n" +
+	"\x0fssh_known_hosts\x18\x0f \x01(\tR\rsshKnownHosts\x12\x1e\n" +
+	"\vssh_key_ref\x18\x10 \x01(\tR\tsshKeyRef\x12\x16\n" +
+	"\x06sha256\x18\x11 \x01(\tR\x06sha256\x12\x19\n" +
+	"\bbase_ref\x18\x12 \x01(\tR\abaseRef\x12#\n" +
+	"\rhistory_depth\x18\x13 \x01(\x05R\fhistoryDepth\x12+\n" +
+	"\x11package_ecosystem\x18\x14 \x01(\tR\x10packageEcosystem\x12!\n" +
+	"\fpackage_name\x18\x15 \x01(\tR\vpackageName\x12'\n" +
+	"\x0fpackage_version\x18\x16 \x01(\tR\x0epackageVersion\x12\x15\n" +
+	"\x06job_id\x18\x17 \x01(\tR\x05jobId\x12!\n" +
+	"\fworkspace_id\x18\x18 \x01(\tR\vworkspaceId\"\xca\x01\n" +
 	"\x10PipelineResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
-	"\adetails\x18\x02 \x01(\tR\adetails2i\n" +
+	"\adetails\x18\x02 \x01(\tR\adetails\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\x12#\n" +
+	"\rconfig_errors\x18\x04 \x03(\tR\fconfigErrors\x12'\n" +
+	"\x0fresolved_commit\x18\x05 \x01(\tR\x0eresolvedCommit\x12\x1f\n" +
+	"\vmerkle_root\x18\x06 \x01(\tR\n" +
+	"merkleRoot\"\xe9\x01\n" +
+	"\fPipelinePlan\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x1a\n" +
+	"\btemplate\x18\x02 \x01(\tR\btemplate\x124\n" +
+	"\x06stages\x18\x03 \x03(\v2\x1c.orchestratorpb.PlannedStageR\x06stages\x12'\n" +
+	"\x0fresolved_commit\x18\x04 \x01(\tR\x0eresolvedCommit\x120\n" +
+	"\x14estimated_size_bytes\x18\x05 \x01(\x03R\x12estimatedSizeBytes\x12\x16\n" +
+	"\x06errors\x18\x06 \x03(\tR\x06errors\"n\n" +
+	"\fPlannedStage\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x1c\n" +
+	"\treachable\x18\x03 \x01(\bR\treachable\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"(\n" +
+	"\x0fWatchJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xb6\x01\n" +
+	"\bJobEvent\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05stage\x18\x03 \x01(\tR\x05stage\x12\x18\n" +
+	"\adetails\x18\x04 \x01(\tR\adetails\x12\x12\n" +
+	"\x04time\x18\x05 \x01(\tR\x04time\x127\n" +
+	"\bprogress\x18\x06 \x01(\v2\x1b.orchestratorpb.JobProgressR\bprogress\"\xa5\x01\n" +
+	"\vJobProgress\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x12\n" +
+	"\x04step\x18\x02 \x01(\tR\x04step\x12\x18\n" +
+	"\apercent\x18\x03 \x01(\x05R\apercent\x12\x12\n" +
+	"\x04done\x18\x04 \x01(\x03R\x04done\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\x12(\n" +
+	"\x10bytes_per_second\x18\x06 \x01(\x03R\x0ebytesPerSecond2\x85\x02\n" +
 	"\x13OrchestratorService\x12R\n" +
-	"\rStartPipeline\x12\x1f.orchestratorpb.PipelineRequest\x1a .orchestratorpb.PipelineResponseB6Z4github.com/unarya/unarya/lib/proto/pb/orchestratorpbb\x06proto3"
+	"\rStartPipeline\x12\x1f.orchestratorpb.PipelineRequest\x1a .orchestratorpb.PipelineResponse\x12Q\n" +
+	"\x10ValidatePipeline\x12\x1f.orchestratorpb.PipelineRequest\x1a\x1c.orchestratorpb.PipelinePlan\x12G\n" +
+	"\bWatchJob\x12\x1f.orchestratorpb.WatchJobRequest\x1a\x18.orchestratorpb.JobEvent0\x01B6Z4github.com/unarya/unarya/lib/proto/pb/orchestratorpbb\x06proto3"
 
 var (
 	file_orchestrator_proto_rawDescOnce sync.Once
@@ -142,19 +784,30 @@ func file_orchestrator_proto_rawDescGZIP() []byte {
 	return file_orchestrator_proto_rawDescData
 }
 
-var file_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_orchestrator_proto_goTypes = []any{
 	(*PipelineRequest)(nil),  // 0: orchestratorpb.PipelineRequest
 	(*PipelineResponse)(nil), // 1: orchestratorpb.PipelineResponse
+	(*PipelinePlan)(nil),     // 2: orchestratorpb.PipelinePlan
+	(*PlannedStage)(nil),     // 3: orchestratorpb.PlannedStage
+	(*WatchJobRequest)(nil),  // 4: orchestratorpb.WatchJobRequest
+	(*JobEvent)(nil),         // 5: orchestratorpb.JobEvent
+	(*JobProgress)(nil),      // 6: orchestratorpb.JobProgress
 }
 var file_orchestrator_proto_depIdxs = []int32{
-	0, // 0: orchestratorpb.OrchestratorService.StartPipeline:input_type -> orchestratorpb.PipelineRequest
-	1, // 1: orchestratorpb.OrchestratorService.StartPipeline:output_type -> orchestratorpb.PipelineResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3, // 0: orchestratorpb.PipelinePlan.stages:type_name -> orchestratorpb.PlannedStage
+	6, // 1: orchestratorpb.JobEvent.progress:type_name -> orchestratorpb.JobProgress
+	0, // 2: orchestratorpb.OrchestratorService.StartPipeline:input_type -> orchestratorpb.PipelineRequest
+	0, // 3: orchestratorpb.OrchestratorService.ValidatePipeline:input_type -> orchestratorpb.PipelineRequest
+	4, // 4: orchestratorpb.OrchestratorService.WatchJob:input_type -> orchestratorpb.WatchJobRequest
+	1, // 5: orchestratorpb.OrchestratorService.StartPipeline:output_type -> orchestratorpb.PipelineResponse
+	2, // 6: orchestratorpb.OrchestratorService.ValidatePipeline:output_type -> orchestratorpb.PipelinePlan
+	5, // 7: orchestratorpb.OrchestratorService.WatchJob:output_type -> orchestratorpb.JobEvent
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_orchestrator_proto_init() }
@@ -168,7 +821,7 @@ func file_orchestrator_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_orchestrator_proto_rawDesc), len(file_orchestrator_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
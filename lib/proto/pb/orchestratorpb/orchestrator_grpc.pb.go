@@ -19,7 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrchestratorService_StartPipeline_FullMethodName = "/orchestratorpb.OrchestratorService/StartPipeline"
+	OrchestratorService_StartPipeline_FullMethodName    = "/orchestratorpb.OrchestratorService/StartPipeline"
+	OrchestratorService_ValidatePipeline_FullMethodName = "/orchestratorpb.OrchestratorService/ValidatePipeline"
+	OrchestratorService_WatchJob_FullMethodName         = "/orchestratorpb.OrchestratorService/WatchJob"
 )
 
 // OrchestratorServiceClient is the client API for OrchestratorService service.
@@ -30,6 +32,11 @@ const (
 // Coordinates execution between Collector, Parser, SecurityScan, and AI
 type OrchestratorServiceClient interface {
 	StartPipeline(ctx context.Context, in *PipelineRequest, opts ...grpc.CallOption) (*PipelineResponse, error)
+	// Check that a pipeline request would work without collecting anything
+	ValidatePipeline(ctx context.Context, in *PipelineRequest, opts ...grpc.CallOption) (*PipelinePlan, error)
+	// Stream a job's status, stage and collection progress until it finishes.
+	// The first event is the job's current state.
+	WatchJob(ctx context.Context, in *WatchJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error)
 }
 
 type orchestratorServiceClient struct {
@@ -50,6 +57,35 @@ func (c *orchestratorServiceClient) StartPipeline(ctx context.Context, in *Pipel
 	return out, nil
 }
 
+func (c *orchestratorServiceClient) ValidatePipeline(ctx context.Context, in *PipelineRequest, opts ...grpc.CallOption) (*PipelinePlan, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PipelinePlan)
+	err := c.cc.Invoke(ctx, OrchestratorService_ValidatePipeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) WatchJob(ctx context.Context, in *WatchJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrchestratorService_ServiceDesc.Streams[0], OrchestratorService_WatchJob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchJobRequest, JobEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_WatchJobClient = grpc.ServerStreamingClient[JobEvent]
+
 // OrchestratorServiceServer is the server API for OrchestratorService service.
 // All implementations must embed UnimplementedOrchestratorServiceServer
 // for forward compatibility.
@@ -58,6 +94,11 @@ func (c *orchestratorServiceClient) StartPipeline(ctx context.Context, in *Pipel
 // Coordinates execution between Collector, Parser, SecurityScan, and AI
 type OrchestratorServiceServer interface {
 	StartPipeline(context.Context, *PipelineRequest) (*PipelineResponse, error)
+	// Check that a pipeline request would work without collecting anything
+	ValidatePipeline(context.Context, *PipelineRequest) (*PipelinePlan, error)
+	// Stream a job's status, stage and collection progress until it finishes.
+	// The first event is the job's current state.
+	WatchJob(*WatchJobRequest, grpc.ServerStreamingServer[JobEvent]) error
 	mustEmbedUnimplementedOrchestratorServiceServer()
 }
 
@@ -71,6 +112,12 @@ type UnimplementedOrchestratorServiceServer struct{}
 func (UnimplementedOrchestratorServiceServer) StartPipeline(context.Context, *PipelineRequest) (*PipelineResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartPipeline not implemented")
 }
+func (UnimplementedOrchestratorServiceServer) ValidatePipeline(context.Context, *PipelineRequest) (*PipelinePlan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidatePipeline not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) WatchJob(*WatchJobRequest, grpc.ServerStreamingServer[JobEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJob not implemented")
+}
 func (UnimplementedOrchestratorServiceServer) mustEmbedUnimplementedOrchestratorServiceServer() {}
 func (UnimplementedOrchestratorServiceServer) testEmbeddedByValue()                             {}
 
@@ -110,6 +157,35 @@ func _OrchestratorService_StartPipeline_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrchestratorService_ValidatePipeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PipelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).ValidatePipeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_ValidatePipeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).ValidatePipeline(ctx, req.(*PipelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_WatchJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchJobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrchestratorServiceServer).WatchJob(m, &grpc.GenericServerStream[WatchJobRequest, JobEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_WatchJobServer = grpc.ServerStreamingServer[JobEvent]
+
 // OrchestratorService_ServiceDesc is the grpc.ServiceDesc for OrchestratorService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -121,7 +197,17 @@ var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "StartPipeline",
 			Handler:    _OrchestratorService_StartPipeline_Handler,
 		},
+		{
+			MethodName: "ValidatePipeline",
+			Handler:    _OrchestratorService_ValidatePipeline_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchJob",
+			Handler:       _OrchestratorService_WatchJob_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "orchestrator.proto",
 }
@@ -22,10 +22,13 @@ const (
 )
 
 type ParseRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SourcePath    string                 `protobuf:"bytes,1,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SourcePath     string                 `protobuf:"bytes,1,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
+	RepoConfig     string                 `protobuf:"bytes,2,opt,name=repo_config,json=repoConfig,proto3" json:"repo_config,omitempty"`             // Validated .unarya.yml as JSON, empty for defaults
+	ArtifactDigest string                 `protobuf:"bytes,3,opt,name=artifact_digest,json=artifactDigest,proto3" json:"artifact_digest,omitempty"` // Fetch the source from the artifact store instead of source_path
+	ChangeSet      string                 `protobuf:"bytes,4,opt,name=change_set,json=changeSet,proto3" json:"change_set,omitempty"`                // Changed files and lines as JSON; empty parses everything
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ParseRequest) Reset() {
@@ -65,12 +68,34 @@ func (x *ParseRequest) GetSourcePath() string {
 	return ""
 }
 
+func (x *ParseRequest) GetRepoConfig() string {
+	if x != nil {
+		return x.RepoConfig
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetArtifactDigest() string {
+	if x != nil {
+		return x.ArtifactDigest
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetChangeSet() string {
+	if x != nil {
+		return x.ChangeSet
+	}
+	return ""
+}
+
 type ParseResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Language       string                 `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`                                // Detected primary language
 	Dependencies   []string               `protobuf:"bytes,2,rep,name=dependencies,proto3" json:"dependencies,omitempty"`                        // List of dependency files and data
 	CodeStructure  string                 `protobuf:"bytes,3,opt,name=code_structure,json=codeStructure,proto3" json:"code_structure,omitempty"` // Tree-like or JSON structure
 	Representation string                 `protobuf:"bytes,4,opt,name=representation,proto3" json:"representation,omitempty"`                    // e.g., "json", "tree"
+	FileMetrics    []*FileMetrics         `protobuf:"bytes,5,rep,name=file_metrics,json=fileMetrics,proto3" json:"file_metrics,omitempty"`       // Source files parsed, or the changed ones with a change set
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -133,19 +158,116 @@ func (x *ParseResponse) GetRepresentation() string {
 	return ""
 }
 
+func (x *ParseResponse) GetFileMetrics() []*FileMetrics {
+	if x != nil {
+		return x.FileMetrics
+	}
+	return nil
+}
+
+type FileMetrics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to the source root with forward slashes, like the collector's manifest and history
+	Lines         int32                  `protobuf:"varint,2,opt,name=lines,proto3" json:"lines,omitempty"`
+	Functions     int32                  `protobuf:"varint,3,opt,name=functions,proto3" json:"functions,omitempty"`   // Go only, like the rest below
+	Classes       int32                  `protobuf:"varint,4,opt,name=classes,proto3" json:"classes,omitempty"`       // Struct types
+	Complexity    int32                  `protobuf:"varint,5,opt,name=complexity,proto3" json:"complexity,omitempty"` // Cyclomatic, summed over the file's functions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileMetrics) Reset() {
+	*x = FileMetrics{}
+	mi := &file_parser_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileMetrics) ProtoMessage() {}
+
+func (x *FileMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_parser_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileMetrics.ProtoReflect.Descriptor instead.
+func (*FileMetrics) Descriptor() ([]byte, []int) {
+	return file_parser_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileMetrics) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileMetrics) GetLines() int32 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+func (x *FileMetrics) GetFunctions() int32 {
+	if x != nil {
+		return x.Functions
+	}
+	return 0
+}
+
+func (x *FileMetrics) GetClasses() int32 {
+	if x != nil {
+		return x.Classes
+	}
+	return 0
+}
+
+func (x *FileMetrics) GetComplexity() int32 {
+	if x != nil {
+		return x.Complexity
+	}
+	return 0
+}
+
 var File_parser_proto protoreflect.FileDescriptor
 
 const file_parser_proto_rawDesc = "" +
 	"\n" +
-	"\fparser.proto\x12\bparserpb\"/\n" +
+	"\fparser.proto\x12\bparserpb\"\x98\x01\n" +
 	"\fParseRequest\x12\x1f\n" +
 	"\vsource_path\x18\x01 \x01(\tR\n" +
-	"sourcePath\"\x9e\x01\n" +
+	"sourcePath\x12\x1f\n" +
+	"\vrepo_config\x18\x02 \x01(\tR\n" +
+	"repoConfig\x12'\n" +
+	"\x0fartifact_digest\x18\x03 \x01(\tR\x0eartifactDigest\x12\x1d\n" +
+	"\n" +
+	"change_set\x18\x04 \x01(\tR\tchangeSet\"\xd8\x01\n" +
 	"\rParseResponse\x12\x1a\n" +
 	"\blanguage\x18\x01 \x01(\tR\blanguage\x12\"\n" +
 	"\fdependencies\x18\x02 \x03(\tR\fdependencies\x12%\n" +
 	"\x0ecode_structure\x18\x03 \x01(\tR\rcodeStructure\x12&\n" +
-	"\x0erepresentation\x18\x04 \x01(\tR\x0erepresentation2M\n" +
+	"\x0erepresentation\x18\x04 \x01(\tR\x0erepresentation\x128\n" +
+	"\ffile_metrics\x18\x05 \x03(\v2\x15.parserpb.FileMetricsR\vfileMetrics\"\x8f\x01\n" +
+	"\vFileMetrics\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05lines\x18\x02 \x01(\x05R\x05lines\x12\x1c\n" +
+	"\tfunctions\x18\x03 \x01(\x05R\tfunctions\x12\x18\n" +
+	"\aclasses\x18\x04 \x01(\x05R\aclasses\x12\x1e\n" +
+	"\n" +
+	"complexity\x18\x05 \x01(\x05R\n" +
+	"complexity2M\n" +
 	"\rParserService\x12<\n" +
 	"\tParseCode\x12\x16.parserpb.ParseRequest\x1a\x17.parserpb.ParseResponseB0Z.github.com/unarya/unarya/lib/proto/pb/parserpbb\x06proto3"
 
@@ -161,19 +283,21 @@ func file_parser_proto_rawDescGZIP() []byte {
 	return file_parser_proto_rawDescData
 }
 
-var file_parser_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_parser_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_parser_proto_goTypes = []any{
 	(*ParseRequest)(nil),  // 0: parserpb.ParseRequest
 	(*ParseResponse)(nil), // 1: parserpb.ParseResponse
+	(*FileMetrics)(nil),   // 2: parserpb.FileMetrics
 }
 var file_parser_proto_depIdxs = []int32{
-	0, // 0: parserpb.ParserService.ParseCode:input_type -> parserpb.ParseRequest
-	1, // 1: parserpb.ParserService.ParseCode:output_type -> parserpb.ParseResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: parserpb.ParseResponse.file_metrics:type_name -> parserpb.FileMetrics
+	0, // 1: parserpb.ParserService.ParseCode:input_type -> parserpb.ParseRequest
+	1, // 2: parserpb.ParserService.ParseCode:output_type -> parserpb.ParseResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_parser_proto_init() }
@@ -187,7 +311,7 @@ func file_parser_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_parser_proto_rawDesc), len(file_parser_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,10 +22,13 @@ const (
 )
 
 type ScanRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SourcePath    string                 `protobuf:"bytes,1,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SourcePath     string                 `protobuf:"bytes,1,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
+	RepoConfig     string                 `protobuf:"bytes,2,opt,name=repo_config,json=repoConfig,proto3" json:"repo_config,omitempty"`             // Validated .unarya.yml as JSON, empty for defaults
+	ArtifactDigest string                 `protobuf:"bytes,3,opt,name=artifact_digest,json=artifactDigest,proto3" json:"artifact_digest,omitempty"` // Fetch the source from the artifact store instead of source_path
+	ChangeSet      string                 `protobuf:"bytes,4,opt,name=change_set,json=changeSet,proto3" json:"change_set,omitempty"`                // Changed files and lines as JSON; findings outside them are dropped
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ScanRequest) Reset() {
@@ -65,6 +68,27 @@ func (x *ScanRequest) GetSourcePath() string {
 	return ""
 }
 
+func (x *ScanRequest) GetRepoConfig() string {
+	if x != nil {
+		return x.RepoConfig
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetArtifactDigest() string {
+	if x != nil {
+		return x.ArtifactDigest
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetChangeSet() string {
+	if x != nil {
+		return x.ChangeSet
+	}
+	return ""
+}
+
 type ScanResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Report        string                 `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`                            // Full JSON report generated by scanner
@@ -121,10 +145,15 @@ var File_security_scan_proto protoreflect.FileDescriptor
 
 const file_security_scan_proto_rawDesc = "" +
 	"\n" +
-	"\x13security_scan.proto\x12\x0esecurityscanpb\".\n" +
+	"\x13security_scan.proto\x12\x0esecurityscanpb\"\x97\x01\n" +
 	"\vScanRequest\x12\x1f\n" +
 	"\vsource_path\x18\x01 \x01(\tR\n" +
-	"sourcePath\"G\n" +
+	"sourcePath\x12\x1f\n" +
+	"\vrepo_config\x18\x02 \x01(\tR\n" +
+	"repoConfig\x12'\n" +
+	"\x0fartifact_digest\x18\x03 \x01(\tR\x0eartifactDigest\x12\x1d\n" +
+	"\n" +
+	"change_set\x18\x04 \x01(\tR\tchangeSet\"G\n" +
 	"\fScanResponse\x12\x16\n" +
 	"\x06report\x18\x01 \x01(\tR\x06report\x12\x1f\n" +
 	"\vtotal_finds\x18\x02 \x01(\x05R\n" +